@@ -1,27 +1,238 @@
 package main
 
 import (
+	"context"
 	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
 
 	cg "github.com/euphoricrhino/cg/lib"
 )
 
 var (
-	j1 = flag.String("j1", "", "j1 value")
-	j2 = flag.String("j2", "", "j2 value")
+	j1       = flag.String("j1", "", "j1 value")
+	j2       = flag.String("j2", "", "j2 value")
+	timeout  = flag.Duration("timeout", 0, "give up computing the table after this long, 0 means no limit")
+	progress = flag.Bool("progress", isTerminal(os.Stderr), "show a progress bar on stderr while computing the table; by default only if stderr is a terminal")
+	cacheDir = flag.String("cache-dir", "", "directory to keep computed tables in across runs, empty means no persistence")
+	allUpTo  = flag.String("all-up-to", "", "instead of --j1 and --j2, render the tables for all j1, j2 up to this value")
+	outDir   = flag.String("out-dir", "", "directory to write the tables of --all-up-to to, defaults to clebsch-gordan in the temp directory")
+	format   = flag.String("format", "html", "output format, one of "+strings.Join(cg.RendererNames(), ", "))
+	out      = flag.String("out", "", "file to write the table to, - for stdout; defaults to a new clebsch-gordan-*.<extension of format> file in the temp directory")
+	columns  = flag.String("columns", "", "comma-separated columns of the csv and tsv formats, out of j1,j2,j,m,m1,m2,square,radical,decimal; all by default")
+	tmplFile = flag.String("template", "", "file of an HTML template to render the html format with instead of the built-in one, see --dump-template")
+	dumpTmpl = flag.Bool("dump-template", false, "print the built-in HTML template as a starting point for --template and exit")
+	pairs    = flag.String("pairs", "", "instead of --j1 and --j2, lay out the tables of several pairs like 1/2x1/2,1x1/2,1x1 on one page, for formats that can, like pdg-html and pdg-svg")
 )
 
+// Returns the renderer of --format and the file extension of its output, with the --template of the html format
+// and the --columns of the csv and tsv formats.
+func newRenderer() (cg.Renderer, string, error) {
+	r, ext, ok := cg.LookupRenderer(*format)
+	if !ok {
+		return nil, "", fmt.Errorf("unknown format %q, want one of %v", *format, strings.Join(cg.RendererNames(), ", "))
+	}
+	if *tmplFile != "" {
+		if *format != "html" {
+			return nil, "", fmt.Errorf("--template only applies to the html format")
+		}
+		text, err := os.ReadFile(*tmplFile)
+		if err != nil {
+			return nil, "", err
+		}
+		tmpl, err := cg.ParseHTMLTemplate(string(text))
+		if err != nil {
+			return nil, "", err
+		}
+		r = &cg.HTMLRenderer{Template: tmpl}
+	}
+	if *columns == "" {
+		return r, ext, nil
+	}
+	cols, err := cg.ParseCSVColumns(*columns)
+	if err != nil {
+		return nil, "", err
+	}
+	opts := &cg.CSVOptions{Columns: cols}
+	switch *format {
+	case "csv":
+	case "tsv":
+		opts.Comma = '\t'
+	default:
+		return nil, "", fmt.Errorf("--columns only applies to the csv and tsv formats")
+	}
+	return cg.RendererFunc(func(t *cg.Table, w io.Writer) error { return t.WriteCSV(w, opts) }), ext, nil
+}
+
 func main() {
 	flag.Parse()
 
-	twoj1, err := cg.ParseHalfInteger(*j1)
+	if err := run(); err != nil {
+		fmt.Fprintf(os.Stderr, "gen-cg-table: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run() error {
+	if *dumpTmpl {
+		fmt.Print(cg.DefaultHTMLTemplate)
+		return nil
+	}
+	if err := checkFlags(); err != nil {
+		return err
+	}
+	r, ext, err := newRenderer()
 	if err != nil {
-		panic(err)
+		return err
 	}
-	twoj2, err := cg.ParseHalfInteger(*j2)
+	if *allUpTo != "" {
+		return runAll(r, ext)
+	}
+	if *pairs != "" {
+		return runPairs(r, ext)
+	}
+	hj1, err := cg.ParseHalfIntegerValue(*j1)
+	if err != nil {
+		return err
+	}
+	hj2, err := cg.ParseHalfIntegerValue(*j2)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := newContext()
+	defer cancel()
+	opts, bar := newOptions()
+	t, err := cg.NewCache(0, *cacheDir).Get(ctx, hj1.Twice(), hj2.Twice(), opts)
+	bar.finish()
 	if err != nil {
-		panic(err)
+		return err
+	}
+	return writeOutput(ext, func(w io.Writer) error { return r.Render(t, w) })
+}
+
+// Rejects flags that do not go together, rather than silently ignoring some of them.
+func checkFlags() error {
+	switch {
+	case *pairs != "" && *allUpTo != "":
+		return fmt.Errorf("--pairs and --all-up-to cannot be used together")
+	case (*pairs != "" || *allUpTo != "") && (*j1 != "" || *j2 != ""):
+		return fmt.Errorf("--j1 and --j2 cannot be used with --pairs or --all-up-to")
+	case *allUpTo != "" && *out != "":
+		return fmt.Errorf("--out cannot be used with --all-up-to, which writes to --out-dir")
+	case *allUpTo == "" && *outDir != "":
+		return fmt.Errorf("--out-dir only applies to --all-up-to")
+	}
+	return nil
+}
+
+// Lays out the tables of --pairs on one page.
+func runPairs(r cg.Renderer, ext string) error {
+	mr, ok := r.(cg.MultiRenderer)
+	if !ok {
+		return fmt.Errorf("format %v cannot lay out the tables of --pairs on one page, pdg-html and pdg-svg can", *format)
+	}
+	type pair struct{ twoj1, twoj2 int }
+	var ps []pair
+	for _, str := range strings.Split(*pairs, ",") {
+		js := strings.Split(strings.TrimSpace(str), "x")
+		if len(js) != 2 {
+			return fmt.Errorf("pair %q must be like 3/2x1", str)
+		}
+		hj1, err := cg.ParseHalfIntegerValue(js[0])
+		if err != nil {
+			return err
+		}
+		hj2, err := cg.ParseHalfIntegerValue(js[1])
+		if err != nil {
+			return err
+		}
+		ps = append(ps, pair{hj1.Twice(), hj2.Twice()})
+	}
+	ctx, cancel := newContext()
+	defer cancel()
+	opts, bar := newOptions()
+	cache := cg.NewCache(0, *cacheDir)
+	tables := make([]*cg.Table, len(ps))
+	for i, p := range ps {
+		t, err := cache.Get(ctx, p.twoj1, p.twoj2, opts)
+		if err != nil {
+			bar.finish()
+			return err
+		}
+		tables[i] = t
+	}
+	bar.finish()
+	return writeOutput(ext, func(w io.Writer) error { return mr.RenderTables(w, tables...) })
+}
+
+// Renders every table with j1, j2 up to --all-up-to into --out-dir, one file per ordered pair.
+func runAll(r cg.Renderer, ext string) error {
+	maxJ, err := cg.ParseHalfIntegerValue(*allUpTo)
+	if err != nil {
+		return err
+	}
+	dir := *outDir
+	if dir == "" {
+		dir = filepath.Join(os.TempDir(), "clebsch-gordan")
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	ctx, cancel := newContext()
+	defer cancel()
+	opts, bar := newOptions()
+	ts, err := cg.ComputeAllContext(ctx, maxJ.Twice(), opts)
+	bar.finish()
+	if err != nil {
+		return err
+	}
+	for twoj1 := 0; twoj1 <= maxJ.Twice(); twoj1++ {
+		for twoj2 := 0; twoj2 <= maxJ.Twice(); twoj2++ {
+			t := ts.Table(twoj1, twoj2)
+			if _, err := cg.WriteOutput(filepath.Join(dir, tableFileName(twoj1, twoj2, ext)), "", func(w io.Writer) error { return r.Render(t, w) }); err != nil {
+				return err
+			}
+		}
+	}
+	fmt.Println(dir)
+	return nil
+}
+
+// Returns the context of the computation, limited by --timeout.
+func newContext() (context.Context, context.CancelFunc) {
+	if *timeout > 0 {
+		return context.WithTimeout(context.Background(), *timeout)
+	}
+	return context.WithCancel(context.Background())
+}
+
+// Returns the options of the computation, with the progress bar drawn if --progress is set.
+func newOptions() (*cg.Options, *progressBar) {
+	opts := &cg.Options{}
+	bar := newProgressBar(os.Stderr)
+	if *progress {
+		opts.Progress = bar.update
+	}
+	return opts, bar
+}
+
+// Names the file of the table for j1, j2 like cg-3_2-1.html, with the given extension.
+func tableFileName(twoj1, twoj2 int, ext string) string {
+	name := func(twoj int) string { return strings.ReplaceAll(cg.FormatHalfInteger(twoj), "/", "_") }
+	return fmt.Sprintf("cg-%v-%v.%v", name(twoj1), name(twoj2), ext)
+}
+
+// Renders to --out, or to stdout if it is -, or to a new file in the temp directory, and prints the name of the file.
+func writeOutput(ext string, render func(w io.Writer) error) error {
+	name, err := cg.WriteOutput(*out, "clebsch-gordan-*."+ext, render)
+	if err != nil {
+		return err
+	}
+	if name != "-" {
+		fmt.Println(name)
 	}
-	t := cg.ComputeCG(twoj1, twoj2)
-	t.RenderHTML()
+	return nil
 }
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+const progressBarWidth = 40
+
+// Draws a progress bar on a terminal, redrawing only when the percentage changes.
+type progressBar struct {
+	w       io.Writer
+	percent int
+}
+
+func newProgressBar(w io.Writer) *progressBar {
+	return &progressBar{w: w, percent: -1}
+}
+
+func (p *progressBar) update(done, total int) {
+	percent := 100
+	if total > 0 {
+		percent = done * 100 / total
+	}
+	if percent == p.percent {
+		return
+	}
+	p.percent = percent
+	filled := progressBarWidth * percent / 100
+	fmt.Fprintf(p.w, "\r[%v%v] %3d%%", strings.Repeat("=", filled), strings.Repeat(" ", progressBarWidth-filled), percent)
+}
+
+// Moves past the bar so later output starts on a fresh line.
+func (p *progressBar) finish() {
+	if p.percent >= 0 {
+		fmt.Fprintln(p.w)
+	}
+}
+
+// Tells if f is a terminal, so a progress bar does not end up in logs and pipes.
+func isTerminal(f *os.File) bool {
+	fi, err := f.Stat()
+	return err == nil && fi.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,397 @@
+package cg
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash"
+	"hash/crc32"
+	"io"
+	"math/big"
+	"math/bits"
+)
+
+// Binary form of a table, see EncodeBinary. All integers, including numerators and denominators of any size, are unsigned
+// varints: 7 bits per byte, least significant first, with the high bit set on all bytes but the last, as in encoding/binary.
+// This keeps the many small coefficients of a table at a byte or two each without a length prefix.
+//
+//	magic      4 bytes "CGTB"
+//	version    1
+//	j1, j2     twice their values, in the order used to create the table
+//	coefficients, column by column (decreasing j), cell by cell (decreasing m >= 0), by decreasing m1 of the larger of j1 and j2, each
+//	  numerator  magnitude shifted left by one, ored with 1 if negative; 0 for a zero coefficient
+//	  denominator (non-zero coefficients only)
+//	  radicand (non-zero coefficients only) word count k, then k words of the prime set, see primeSet
+//	checksum   4 bytes, big-endian CRC-32 (IEEE) of everything before
+//
+// A coefficient is r√s with r the signed fraction and s the product of the primes in the set, see radical.
+const (
+	binaryMagic   = "CGTB"
+	binaryVersion = 1
+	// Bounds the bytes of a varint, so corrupted data does not cause huge allocations; a magnitude of 1 MiB takes fewer.
+	maxVarintBytes = 1 << 21
+	// Slots allocated for a column before its coefficients arrive, see binaryDecoder.column.
+	initialColumnSlots = 1 << 12
+)
+
+// EncodeBinary writes the table to w in the compact binary form, which is much smaller and faster to read than JSON.
+// Failures are reported as *WriteError.
+func EncodeBinary(w io.Writer, t *Table) error {
+	bw := bufio.NewWriter(w)
+	crc := crc32.NewIEEE()
+	e := &binaryEncoder{w: io.MultiWriter(bw, crc)}
+	e.bytes([]byte(binaryMagic))
+	e.uvarint(binaryVersion)
+	e.uvarint(uint64(t.J1().Twice()))
+	e.uvarint(uint64(t.J2().Twice()))
+	for _, col := range t.columns {
+		for _, cell := range col.cells {
+			for l := 0; l < cell.size(); l++ {
+				e.radical(cell.at(l))
+			}
+		}
+	}
+	if e.err == nil {
+		_, e.err = bw.Write(crc.Sum(nil))
+	}
+	if e.err == nil {
+		e.err = bw.Flush()
+	}
+	if e.err != nil {
+		return &WriteError{Err: e.err}
+	}
+	return nil
+}
+
+// Writes the parts of the binary form, keeping the first error.
+type binaryEncoder struct {
+	w   io.Writer
+	buf []byte
+	err error
+}
+
+func (e *binaryEncoder) bytes(b []byte) {
+	if e.err == nil {
+		_, e.err = e.w.Write(b)
+	}
+}
+
+func (e *binaryEncoder) uvarint(v uint64) {
+	e.buf = binary.AppendUvarint(e.buf[:0], v)
+	e.bytes(e.buf)
+}
+
+// Writes a non-negative integer of any size as a varint.
+func (e *binaryEncoder) bigUvarint(z *big.Int) {
+	if z.IsUint64() {
+		e.uvarint(z.Uint64())
+		return
+	}
+	words, n := z.Bits(), z.BitLen()
+	e.buf = e.buf[:0]
+	for i := 0; i < n; i += 7 {
+		w, off := i/bits.UintSize, i%bits.UintSize
+		v := uint64(words[w]) >> off
+		if off > bits.UintSize-7 && w+1 < len(words) {
+			v |= uint64(words[w+1]) << (bits.UintSize - off)
+		}
+		b := byte(v & 0x7f)
+		if i+7 < n {
+			b |= 0x80
+		}
+		e.buf = append(e.buf, b)
+	}
+	e.bytes(e.buf)
+}
+
+func (e *binaryEncoder) radical(x radical) {
+	if x.isZero() {
+		e.uvarint(0)
+		return
+	}
+	// Small rationals have a numerator above math.MinInt64, so shifting its magnitude does not overflow.
+	if x.r.b == nil {
+		header := uint64(abs64(x.r.n)) << 1
+		if x.r.n < 0 {
+			header |= 1
+		}
+		e.uvarint(header)
+		e.uvarint(uint64(x.r.d))
+	} else {
+		header := BlankInt().Abs(x.r.b.Num())
+		header.Lsh(header, 1)
+		if x.r.sign() < 0 {
+			header.SetBit(header, 0, 1)
+		}
+		e.bigUvarint(header)
+		e.bigUvarint(x.r.b.Denom())
+	}
+	s := x.s
+	for len(s) > 0 && s[len(s)-1] == 0 {
+		s = s[:len(s)-1]
+	}
+	e.uvarint(uint64(len(s)))
+	for _, w := range s {
+		e.uvarint(w)
+	}
+}
+
+// DecodeBinary reads a table written by EncodeBinary.
+// The input is decoded as it is read, with the checksum verified at the end. The header is checked before anything is
+// allocated for the table, and the coefficients of each column are stored as they arrive, so corrupted, truncated or
+// forged input never causes allocations much larger than the input read.
+// Such input is reported as *TableDataError.
+func DecodeBinary(r io.Reader) (*Table, error) {
+	d := newBinaryDecoder(r)
+	magic := make([]byte, len(binaryMagic))
+	if err := d.read(magic); err != nil {
+		return nil, err
+	}
+	if string(magic) != binaryMagic {
+		return nil, binaryError("not a CG table file", nil)
+	}
+	version, err := d.uvarint()
+	if err != nil {
+		return nil, err
+	}
+	if version != binaryVersion {
+		return nil, binaryError(fmt.Sprintf("unsupported version %v", version), nil)
+	}
+	twoj1, err := d.uvarint()
+	if err != nil {
+		return nil, err
+	}
+	twoj2, err := d.uvarint()
+	if err != nil {
+		return nil, err
+	}
+	if twoj1 >= factorLimit || twoj2 >= factorLimit || twoj1+twoj2+1 >= factorLimit {
+		return nil, binaryError(fmt.Sprintf("j1 = %v and j2 = %v out of range", FormatHalfInteger(int(twoj1)), FormatHalfInteger(int(twoj2))), nil)
+	}
+	exchanged := twoj1 < twoj2
+	if exchanged {
+		twoj1, twoj2 = twoj2, twoj1
+	}
+	// Unlike newTable, the columns are only allocated once their coefficients are read.
+	t := &Table{
+		exchanged: exchanged,
+		twoj1:     int(twoj1),
+		twoj2:     int(twoj2),
+		columns:   make([]*column, twoj2+1),
+	}
+	t.store = newStore(t.maxPrime())
+	for dj := range t.columns {
+		if t.columns[dj], err = d.column(t, dj); err != nil {
+			return nil, err
+		}
+	}
+	sum := d.sum()
+	var stored [crc32.Size]byte
+	if _, err := io.ReadFull(d.r, stored[:]); err != nil {
+		return nil, binaryError("truncated", io.ErrUnexpectedEOF)
+	}
+	if binary.BigEndian.Uint32(stored[:]) != sum {
+		return nil, binaryError("checksum mismatch, the data is corrupted", nil)
+	}
+	if _, err := d.r.ReadByte(); err != io.EOF {
+		return nil, binaryError("bytes after the checksum", err)
+	}
+	return t, nil
+}
+
+func binaryError(reason string, err error) error {
+	return &TableDataError{Format: "binary", Reason: reason, Err: err}
+}
+
+// Reads the parts of the binary form, adding the bytes read to the checksum.
+type binaryDecoder struct {
+	r   *bufio.Reader
+	crc hash.Hash32
+	// Bytes read but not yet added to the checksum, which is much faster in batches than byte by byte.
+	pending []byte
+	// Reused for the bytes of large varints.
+	buf []byte
+}
+
+func newBinaryDecoder(r io.Reader) *binaryDecoder {
+	return &binaryDecoder{r: bufio.NewReader(r), crc: crc32.NewIEEE(), pending: make([]byte, 0, 4096)}
+}
+
+func (d *binaryDecoder) ReadByte() (byte, error) {
+	b, err := d.r.ReadByte()
+	if err != nil {
+		return 0, err
+	}
+	d.pending = append(d.pending, b)
+	if len(d.pending) == cap(d.pending) {
+		d.crc.Write(d.pending)
+		d.pending = d.pending[:0]
+	}
+	return b, nil
+}
+
+// Reads a varint of at most 64 bits straight from the buffer of the reader, if it holds all of it.
+func (d *binaryDecoder) peekUvarint() (uint64, bool) {
+	b, _ := d.r.Peek(binary.MaxVarintLen64)
+	v, n := binary.Uvarint(b)
+	if n <= 0 {
+		return 0, false
+	}
+	d.consume(b[:n])
+	return v, true
+}
+
+// Adds the bytes b, just peeked from the reader, to the checksum and skips them.
+func (d *binaryDecoder) consume(b []byte) {
+	if len(d.pending)+len(b) > cap(d.pending) {
+		d.crc.Write(d.pending)
+		d.pending = d.pending[:0]
+	}
+	if len(b) > cap(d.pending) {
+		d.crc.Write(b)
+	} else {
+		d.pending = append(d.pending, b...)
+	}
+	d.r.Discard(len(b))
+}
+
+func (d *binaryDecoder) read(b []byte) error {
+	if _, err := io.ReadFull(d.r, b); err != nil {
+		return binaryError("truncated", io.ErrUnexpectedEOF)
+	}
+	d.crc.Write(d.pending)
+	d.pending = d.pending[:0]
+	d.crc.Write(b)
+	return nil
+}
+
+// Returns the checksum of all bytes read so far.
+func (d *binaryDecoder) sum() uint32 {
+	d.crc.Write(d.pending)
+	d.pending = d.pending[:0]
+	return d.crc.Sum32()
+}
+
+func (d *binaryDecoder) uvarint() (uint64, error) {
+	if v, ok := d.peekUvarint(); ok {
+		return v, nil
+	}
+	v, err := binary.ReadUvarint(d)
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return 0, binaryError("truncated", io.ErrUnexpectedEOF)
+	}
+	if err != nil {
+		return 0, binaryError("bad varint", err)
+	}
+	return v, nil
+}
+
+// Reads a varint of any size into z.
+func (d *binaryDecoder) bigUvarint(z *big.Int) error {
+	// Gather the bytes of the varint in buf, from as much of the buffer of the reader as it takes.
+	d.buf = d.buf[:0]
+	for {
+		if _, err := d.r.Peek(1); err != nil {
+			return binaryError("truncated", io.ErrUnexpectedEOF)
+		}
+		b, _ := d.r.Peek(d.r.Buffered())
+		n := 0
+		for n < len(b) && b[n]&0x80 != 0 {
+			n++
+		}
+		last := n < len(b)
+		if last {
+			n++
+		}
+		if last && len(d.buf) == 0 && n < binary.MaxVarintLen64 {
+			// Most values fit in 63 bits.
+			v, _ := binary.Uvarint(b)
+			z.SetUint64(v)
+			d.consume(b[:n])
+			return nil
+		}
+		if len(d.buf)+n > maxVarintBytes {
+			return binaryError(fmt.Sprintf("varint of more than %v bytes", maxVarintBytes), nil)
+		}
+		d.buf = append(d.buf, b[:n]...)
+		d.consume(b[:n])
+		if last {
+			break
+		}
+	}
+	words := make([]big.Word, (7*len(d.buf)+bits.UintSize-1)/bits.UintSize)
+	for i, b := range d.buf {
+		v := big.Word(b & 0x7f)
+		w, off := 7*i/bits.UintSize, 7*i%bits.UintSize
+		words[w] |= v << off
+		if off > bits.UintSize-7 {
+			words[w+1] |= v >> (bits.UintSize - off)
+		}
+	}
+	z.SetBits(words)
+	return nil
+}
+
+// Reads the coefficients of column dj of t. Its slots grow as the coefficients arrive instead of being allocated
+// for the whole column up front, so a header claiming a huge table only costs the layout of one column.
+func (d *binaryDecoder) column(t *Table, dj int) (*column, error) {
+	col, slots := newColumnCells(t, dj)
+	w := t.store.radicandWords
+	for k := 0; k < slots; k++ {
+		if k == len(col.n) {
+			// Double the slots, up to exactly those of the column.
+			n := 2 * k
+			if n < initialColumnSlots {
+				n = initialColumnSlots
+			}
+			if n > slots {
+				n = slots
+			}
+			col.n = append(make([]int64, 0, n), col.n...)[:n]
+			col.d = append(make([]int64, 0, n), col.d...)[:n]
+			col.s = append(make([]uint64, 0, n*w), col.s...)[:n*w]
+		}
+		x, err := d.radical(w)
+		if err != nil {
+			return nil, err
+		}
+		col.save(k, x)
+	}
+	return col, nil
+}
+
+// Reads a coefficient whose prime set has at most the given number of words.
+func (d *binaryDecoder) radical(radicandWords int) (radical, error) {
+	// The denominator of a blank rat is set, so Denom returns a reference to it rather than a new Int.
+	r := BlankRat()
+	header := r.Num()
+	if err := d.bigUvarint(header); err != nil || header.Sign() == 0 {
+		return radical{}, err
+	}
+	neg := header.Bit(0) != 0
+	header.Rsh(header, 1)
+	if err := d.bigUvarint(r.Denom()); err != nil {
+		return radical{}, err
+	}
+	if r.Num().Sign() == 0 || r.Denom().Sign() == 0 {
+		return radical{}, binaryError("zero numerator or denominator", nil)
+	}
+	if neg {
+		r.Num().Neg(r.Num())
+	}
+	k, err := d.uvarint()
+	if err != nil {
+		return radical{}, err
+	}
+	if k > uint64(radicandWords) {
+		return radical{}, binaryError("radicand out of range", nil)
+	}
+	s := make(primeSet, k)
+	for i := range s {
+		if s[i], err = d.uvarint(); err != nil {
+			return radical{}, err
+		}
+	}
+	return radical{r: ratFromBig(r), s: s}, nil
+}
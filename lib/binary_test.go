@@ -0,0 +1,105 @@
+package cg
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"math/big"
+	"runtime"
+	"testing"
+)
+
+func TestBinaryRoundTrip(t *testing.T) {
+	for _, j := range [][2]int{{0, 0}, {1, 0}, {3, 2}, {2, 5}, {12, 12}, {60, 41}} {
+		table := ComputeCG(j[0], j[1])
+		var buf bytes.Buffer
+		if err := EncodeBinary(&buf, table); err != nil {
+			t.Fatal(err)
+		}
+		loaded, err := DecodeBinary(&buf)
+		if err != nil {
+			t.Fatalf("DecodeBinary of the table for 2j1 = %v, 2j2 = %v: %v", j[0], j[1], err)
+		}
+		if loaded.J1() != table.J1() || loaded.J2() != table.J2() {
+			t.Fatalf("loaded j1 = %v, j2 = %v, want %v, %v", loaded.J1(), loaded.J2(), table.J1(), table.J2())
+		}
+		table.Each(func(j, m, m1, m2 HalfInteger, c SignedSqrt) bool {
+			if got := loaded.QueryHalf(j, m, m1, m2); got.Cmp(c.SignedSquare()) != 0 {
+				t.Errorf("loaded ⟨%v,%v;%v,%v|%v,%v⟩ = %v, want %v", table.J1(), m1, table.J2(), m2, j, m, got.RatString(), c.SignedSquare().RatString())
+			}
+			return true
+		})
+	}
+}
+
+// Appends the checksum to forged data, so only the other checks can refuse it.
+func withChecksum(b []byte) []byte {
+	return binary.BigEndian.AppendUint32(b, crc32.ChecksumIEEE(b))
+}
+
+func TestDecodeBinaryRejectsBadInput(t *testing.T) {
+	header := func(twoj1, twoj2 uint64) []byte {
+		b := append([]byte(binaryMagic), binaryVersion)
+		b = binary.AppendUvarint(b, twoj1)
+		return binary.AppendUvarint(b, twoj2)
+	}
+	var valid bytes.Buffer
+	if err := EncodeBinary(&valid, ComputeCG(3, 2)); err != nil {
+		t.Fatal(err)
+	}
+	for name, b := range map[string][]byte{
+		"no checksum":    header(40000, 20000),
+		"huge j":         withChecksum(header(40000, 20000)),
+		"large j":        withChecksum(header(2000, 2000)),
+		"truncated":      valid.Bytes()[:valid.Len()-5],
+		"corrupted":      append(append([]byte{}, valid.Bytes()[:10]...), append([]byte{valid.Bytes()[10] ^ 1}, valid.Bytes()[11:]...)...),
+		"not a table":    []byte("hello"),
+		"empty":          nil,
+		"other version":  withChecksum(append([]byte(binaryMagic), binaryVersion+1, 6, 4)),
+		"trailing bytes": withChecksum(append(append([]byte{}, valid.Bytes()[:valid.Len()-4]...), 0)),
+	} {
+		var dataErr *TableDataError
+		if _, err := DecodeBinary(bytes.NewReader(b)); !errors.As(err, &dataErr) {
+			t.Errorf("DecodeBinary of %v returned %v, want *TableDataError", name, err)
+		}
+	}
+}
+
+// A short header must not make the decoder allocate the huge table it claims.
+func TestDecodeBinaryBoundsAllocation(t *testing.T) {
+	b := withChecksum(append([]byte(binaryMagic), binaryVersion, 0xc0, 0xd4, 0x01, 0x9e, 0xd4, 0x01)) // 2j1 = 27200, 2j2 = 27166.
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	if _, err := DecodeBinary(bytes.NewReader(b)); err == nil {
+		t.Fatal("DecodeBinary of a forged header succeeded")
+	}
+	runtime.ReadMemStats(&after)
+	// The claimed table has trillions of coefficients, the layout of one column takes about a MiB.
+	if allocated := after.TotalAlloc - before.TotalAlloc; allocated > 8<<20 {
+		t.Errorf("DecodeBinary of a %v-byte forged header allocated %v bytes", len(b), allocated)
+	}
+}
+
+func TestBigUvarint(t *testing.T) {
+	values := []*big.Int{big.NewInt(0), big.NewInt(1), big.NewInt(127), big.NewInt(128), BlankInt().SetUint64(1<<64 - 1)}
+	for _, k := range []uint{63, 64, 65, 127, 128, 300, 1000} {
+		z := BlankInt().Lsh(big.NewInt(1), k)
+		values = append(values, z, BlankInt().Sub(z, big.NewInt(1)), BlankInt().Add(z, big.NewInt(12345)))
+	}
+	var buf bytes.Buffer
+	e := &binaryEncoder{w: &buf}
+	for _, v := range values {
+		e.bigUvarint(v)
+	}
+	d := newBinaryDecoder(&buf)
+	for _, v := range values {
+		got := BlankInt()
+		if err := d.bigUvarint(got); err != nil {
+			t.Fatalf("bigUvarint for %v: %v", v, err)
+		}
+		if got.Cmp(v) != 0 {
+			t.Errorf("bigUvarint read %v, want %v", got, v)
+		}
+	}
+}
@@ -0,0 +1,199 @@
+package cg
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// Cache memoizes computed CG tables so they can be shared within a process and, optionally, across processes.
+// It is safe for concurrent use: concurrent requests for the same table compute it once,
+// and requests for (j1, j2) and (j2, j1) share one entry.
+// Once the estimated memory of the cached tables exceeds the budget, the least recently used ones are dropped.
+type Cache struct {
+	maxMemory int64
+	dir       string
+
+	mu      sync.Mutex
+	entries map[cacheKey]*cacheEntry
+	// Completed entries, most recently used first.
+	lru    *list.List
+	memory int64
+}
+
+// Identifies a table by its doubled j1 and j2, with jmax >= jmin.
+type cacheKey struct {
+	jmax int
+	jmin int
+}
+
+type cacheEntry struct {
+	key cacheKey
+	// Closed once t and err are set.
+	done chan struct{}
+	t    *Table
+	err  error
+	// Estimated memory of t, see EstimateMemory.
+	size int64
+	// Position in Cache.lru, nil while the table is being computed.
+	elem *list.Element
+}
+
+// NewCache returns a cache holding tables up to an estimated maxMemory bytes in total, or without limit if maxMemory <= 0.
+// The most recently used table is always kept, even if it alone exceeds the budget.
+// If dir is not empty, computed tables are also stored in that directory and loaded from it when not in memory,
+// which makes repeated runs for large j fast. The directory is created when needed.
+// Storing is best effort, a table that cannot be written is still returned.
+func NewCache(maxMemory int64, dir string) *Cache {
+	return &Cache{
+		maxMemory: maxMemory,
+		dir:       dir,
+		entries:   make(map[cacheKey]*cacheEntry),
+		lru:       list.New(),
+	}
+}
+
+// Get returns the CG table for the given j1 and j2, computing it with ComputeCGContext if it is neither in memory nor in the cache directory.
+// Arguments are twice the value of actual j1 and j2 so they are integers.
+// Options are used only if the table is computed by this call, a caller waiting on the computation of another one only stops on ctx.
+// The returned table may share its coefficients with other callers, which is safe since tables are never modified.
+func (c *Cache) Get(ctx context.Context, twoj1, twoj2 int, opts *Options) (*Table, error) {
+	if twoj1 < 0 || twoj2 < 0 {
+		return nil, &InvalidJError{TwoJ1: twoj1, TwoJ2: twoj2}
+	}
+	key := cacheKey{jmax: twoj1, jmin: twoj2}
+	if twoj1 < twoj2 {
+		key = cacheKey{jmax: twoj2, jmin: twoj1}
+	}
+	for {
+		c.mu.Lock()
+		e, ok := c.entries[key]
+		if !ok {
+			e = &cacheEntry{key: key, done: make(chan struct{})}
+			c.entries[key] = e
+			c.mu.Unlock()
+			c.fill(ctx, e, opts)
+		} else {
+			if e.elem != nil {
+				c.lru.MoveToFront(e.elem)
+			}
+			c.mu.Unlock()
+			select {
+			case <-e.done:
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+		}
+		if e.err != nil {
+			// The computation was given up by the caller that started it, try again on our own behalf.
+			if ok && isContextError(e.err) && ctx.Err() == nil {
+				continue
+			}
+			return nil, e.err
+		}
+		return e.t.view(twoj1 < twoj2), nil
+	}
+}
+
+// Len returns the number of tables held in memory.
+func (c *Cache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.lru.Len()
+}
+
+// Memory returns the estimated memory in bytes of the tables held in memory.
+func (c *Cache) Memory() int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.memory
+}
+
+// Loads or computes the table of the new entry e, then publishes it and evicts others over the budget.
+// Failed entries are removed so later requests try again.
+func (c *Cache) fill(ctx context.Context, e *cacheEntry, opts *Options) {
+	t, err := c.load(e.key)
+	if err != nil {
+		t, err = ComputeCGContext(ctx, e.key.jmax, e.key.jmin, opts)
+		if err == nil {
+			c.store(t)
+		}
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e.t, e.err = t, err
+	close(e.done)
+	if err != nil {
+		delete(c.entries, e.key)
+		return
+	}
+	e.size = EstimateMemory(e.key.jmax, e.key.jmin)
+	e.elem = c.lru.PushFront(e)
+	c.memory += e.size
+	for c.maxMemory > 0 && c.memory > c.maxMemory && c.lru.Len() > 1 {
+		last := c.lru.Remove(c.lru.Back()).(*cacheEntry)
+		delete(c.entries, last.key)
+		c.memory -= last.size
+	}
+}
+
+// Returns the file in the cache directory holding the table for the given key.
+func (c *Cache) path(key cacheKey) string {
+	return filepath.Join(c.dir, fmt.Sprintf("cg-%v-%v.cgtb", key.jmax, key.jmin))
+}
+
+// Reads the table for the given key from the cache directory.
+func (c *Cache) load(key cacheKey) (*Table, error) {
+	if c.dir == "" {
+		return nil, os.ErrNotExist
+	}
+	f, err := os.Open(c.path(key))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	t, err := DecodeBinary(f)
+	if err != nil {
+		return nil, err
+	}
+	if t.twoj1 != key.jmax || t.twoj2 != key.jmin {
+		return nil, fmt.Errorf("%v holds the table for j1=%v, j2=%v", f.Name(), FormatHalfInteger(t.twoj1), FormatHalfInteger(t.twoj2))
+	}
+	return t, nil
+}
+
+// Writes t to the cache directory, through a temporary file so concurrent processes never read a partial table.
+func (c *Cache) store(t *Table) {
+	if c.dir == "" {
+		return
+	}
+	if err := os.MkdirAll(c.dir, 0o755); err != nil {
+		return
+	}
+	f, err := os.CreateTemp(c.dir, "cg-*.tmp")
+	if err != nil {
+		return
+	}
+	// CreateTemp makes the file private, cached tables should be readable like any other file.
+	err = f.Chmod(0o644)
+	if err == nil {
+		err = EncodeBinary(f, t)
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(f.Name(), c.path(cacheKey{jmax: t.twoj1, jmin: t.twoj2}))
+	}
+	if err != nil {
+		os.Remove(f.Name())
+	}
+}
+
+func isContextError(err error) bool {
+	return errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)
+}
@@ -0,0 +1,212 @@
+package cg
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+// Returns options counting the completed computations in *n.
+func countingOptions(n *int32) *Options {
+	return &Options{Progress: func(done, total int) {
+		if done == total {
+			atomic.AddInt32(n, 1)
+		}
+	}}
+}
+
+// Checks that got holds the same coefficients as a freshly computed table for its j1 and j2.
+func checkTable(t *testing.T, got *Table) {
+	t.Helper()
+	want, err := ComputeCGE(got.J1().Twice(), got.J2().Twice())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var wantEntries []Entry
+	want.Each(func(j, m, m1, m2 HalfInteger, c SignedSqrt) bool {
+		wantEntries = append(wantEntries, Entry{J: j, M: m, M1: m1, M2: m2, C: c})
+		return true
+	})
+	i := 0
+	got.Each(func(j, m, m1, m2 HalfInteger, c SignedSqrt) bool {
+		if i >= len(wantEntries) {
+			t.Errorf("%v×%v has more coefficients than a computed table", got.J1(), got.J2())
+			return false
+		}
+		w := wantEntries[i]
+		if w.J != j || w.M != m || w.M1 != m1 || w.M2 != m2 || w.C.Cmp(c) != 0 {
+			t.Errorf("%v×%v: ⟨%v,%v|%v,%v⟩ = %v, want ⟨%v,%v|%v,%v⟩ = %v", got.J1(), got.J2(), m1, m2, j, m, c, w.M1, w.M2, w.J, w.M, w.C)
+			return false
+		}
+		i++
+		return true
+	})
+	if i != len(wantEntries) {
+		t.Errorf("%v×%v has %v coefficients, want %v", got.J1(), got.J2(), i, len(wantEntries))
+	}
+}
+
+func TestCacheComputesOnce(t *testing.T) {
+	c := NewCache(0, "")
+	var computed int32
+	opts := countingOptions(&computed)
+	const n = 8
+	tables := make([]*Table, n)
+	start := make(chan struct{})
+	var wg sync.WaitGroup
+	for i := range tables {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			<-start
+			var err error
+			if tables[i], err = c.Get(context.Background(), 20, 16, opts); err != nil {
+				t.Error(err)
+			}
+		}(i)
+	}
+	close(start)
+	wg.Wait()
+	if computed != 1 {
+		t.Errorf("%v concurrent requests computed the table %v times, want once", n, computed)
+	}
+	for _, table := range tables[1:] {
+		if table == nil || &table.columns[0] != &tables[0].columns[0] {
+			t.Fatalf("concurrent requests returned tables not sharing their coefficients")
+		}
+	}
+	checkTable(t, tables[0])
+}
+
+func TestCacheSharesExchanged(t *testing.T) {
+	c := NewCache(0, "")
+	var computed int32
+	opts := countingOptions(&computed)
+	t52, err := c.Get(context.Background(), 5, 2, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t25, err := c.Get(context.Background(), 2, 5, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if computed != 1 || c.Len() != 1 {
+		t.Errorf("(5/2, 1) and (1, 5/2) computed %v tables and hold %v, want one", computed, c.Len())
+	}
+	if &t52.columns[0] != &t25.columns[0] {
+		t.Errorf("(5/2, 1) and (1, 5/2) do not share their coefficients")
+	}
+	if t25.J1().Twice() != 2 || t25.J2().Twice() != 5 {
+		t.Errorf("Get(2, 5) returned the table of j1 = %v, j2 = %v", t25.J1(), t25.J2())
+	}
+	checkTable(t, t52)
+	checkTable(t, t25)
+	// The view of the second caller does not change the first.
+	if t52.J1().Twice() != 5 {
+		t.Errorf("Get(2, 5) exchanged the table returned by Get(5, 2)")
+	}
+}
+
+func TestCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	a, b, d := EstimateMemory(4, 4), EstimateMemory(6, 6), EstimateMemory(8, 8)
+	c := NewCache(a+b+d-1, "")
+	var computed int32
+	opts := countingOptions(&computed)
+	get := func(twoj int) {
+		t.Helper()
+		if _, err := c.Get(context.Background(), twoj, twoj, opts); err != nil {
+			t.Fatal(err)
+		}
+	}
+	get(4)
+	get(6)
+	// Makes 6 the least recently used.
+	get(4)
+	if computed != 2 || c.Len() != 2 || c.Memory() != a+b {
+		t.Fatalf("computed %v tables, holding %v of %v bytes, want 2 tables of %v bytes", computed, c.Len(), c.Memory(), a+b)
+	}
+	get(8)
+	if c.Len() != 2 || c.Memory() != a+d {
+		t.Errorf("after exceeding the budget, holding %v tables of %v bytes, want 2 of %v bytes", c.Len(), c.Memory(), a+d)
+	}
+	computed = 0
+	get(4)
+	get(8)
+	if computed != 0 {
+		t.Errorf("recently used tables were evicted")
+	}
+	get(6)
+	if computed != 1 {
+		t.Errorf("the least recently used table was not evicted")
+	}
+
+	// The most recently used table is kept even if it alone exceeds the budget.
+	c = NewCache(1, "")
+	get(4)
+	get(6)
+	if c.Len() != 1 || c.Memory() != b {
+		t.Errorf("holding %v tables of %v bytes over a budget of 1 byte, want the last one of %v bytes", c.Len(), c.Memory(), b)
+	}
+}
+
+func TestCacheDirectory(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "tables")
+	var computed int32
+	opts := countingOptions(&computed)
+	if _, err := NewCache(0, dir).Get(context.Background(), 7, 4, opts); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "cg-7-4.cgtb")); err != nil {
+		t.Fatalf("the table was not stored: %v", err)
+	}
+	// Another cache, as in a later process, loads the table instead of computing it, in either order of j1 and j2.
+	loaded, err := NewCache(0, dir).Get(context.Background(), 4, 7, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if computed != 1 {
+		t.Errorf("the table was computed %v times, want once", computed)
+	}
+	checkTable(t, loaded)
+	if matches, _ := filepath.Glob(filepath.Join(dir, "*.tmp")); len(matches) != 0 {
+		t.Errorf("temporary files left in the cache directory: %v", matches)
+	}
+}
+
+func TestCacheRecoversFromCorruptFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "cg-7-4.cgtb")
+	var computed int32
+	opts := countingOptions(&computed)
+	for _, corrupt := range [][]byte{[]byte("not a table"), nil} {
+		if corrupt == nil {
+			// A valid file of another table under this name.
+			if _, err := NewCache(0, dir).Get(context.Background(), 6, 4, nil); err != nil {
+				t.Fatal(err)
+			}
+			var err error
+			if corrupt, err = os.ReadFile(filepath.Join(dir, "cg-6-4.cgtb")); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := os.WriteFile(path, corrupt, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		computed = 0
+		table, err := NewCache(0, dir).Get(context.Background(), 7, 4, opts)
+		if err != nil {
+			t.Fatalf("Get with a corrupt cache file: %v", err)
+		}
+		if computed != 1 {
+			t.Errorf("the table was computed %v times with a corrupt cache file, want once", computed)
+		}
+		checkTable(t, table)
+		// The file was replaced with the computed table.
+		if _, err := NewCache(0, dir).Get(context.Background(), 7, 4, opts); err != nil || computed != 1 {
+			t.Errorf("the corrupt cache file was not replaced")
+		}
+	}
+}
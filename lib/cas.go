@@ -0,0 +1,231 @@
+package cg
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// CASLanguage names the source language of a computer algebra system that coefficients can be exported to, see Table.WriteCAS.
+type CASLanguage string
+
+const (
+	// Wolfram Language, e.g., -Sqrt[2/5], evaluated by Get.
+	Mathematica CASLanguage = "mathematica"
+	// Python with SymPy, e.g., -sqrt(Rational(2, 5)).
+	SymPy CASLanguage = "sympy"
+	// Julia, e.g., -sqrt(2//5), a Float64 like WignerSymbols.clebschgordan gives.
+	// Julia has no exact square roots, so CASWriter also writes the exact signed squares, see CASWriter.Begin.
+	Julia CASLanguage = "julia"
+)
+
+// AllCASLanguages lists all languages.
+var AllCASLanguages = []CASLanguage{Mathematica, SymPy, Julia}
+
+func (l CASLanguage) valid() bool {
+	for _, lang := range AllCASLanguages {
+		if l == lang {
+			return true
+		}
+	}
+	return false
+}
+
+// PhaseConvention describes the phase convention of the coefficients, naming the function of the system that agrees with them.
+func (l CASLanguage) PhaseConvention() string {
+	fn := ""
+	switch l {
+	case Mathematica:
+		fn = "ClebschGordan[{j1, m1}, {j2, m2}, {j, m}]"
+	case SymPy:
+		fn = "sympy.physics.quantum.cg.CG(j1, m1, j2, m2, j, m).doit()"
+	case Julia:
+		fn = "WignerSymbols.clebschgordan(j1, m1, j2, m2, j, m)"
+	}
+	return fmt.Sprintf("Phase convention: Condon-Shortley, same as %v.", fn)
+}
+
+// HalfInteger formats h as an exact number, e.g., "-7/2" for Mathematica, "Rational(-7, 2)" for SymPy, "-7//2" for Julia.
+func (l CASLanguage) HalfInteger(h HalfInteger) string {
+	if h.IsInteger() {
+		return h.String()
+	}
+	switch l {
+	case SymPy:
+		return fmt.Sprintf("Rational(%v, 2)", h.Twice())
+	case Julia:
+		return fmt.Sprintf("%v//2", h.Twice())
+	}
+	return h.String()
+}
+
+// SignedSqrt formats c as the signed square root of an exact fraction, e.g., "-Sqrt[2/5]" for Mathematica,
+// "-sqrt(Rational(2, 5))" for SymPy and "-sqrt(2//5)" for Julia. Integer squares are written without fraction, e.g., "Sqrt[3]", and ±1 as such.
+// The Julia form evaluates to a Float64.
+func (l CASLanguage) SignedSqrt(c SignedSqrt) string {
+	if c.IsZero() {
+		return "0"
+	}
+	str := ""
+	if c.Sign() < 0 {
+		str = "-"
+	}
+	sq := c.Square()
+	if sq.IsInt() && isOne(sq.Num()) {
+		return str + "1"
+	}
+	frac := sq.Num().String()
+	if !sq.IsInt() {
+		switch l {
+		case SymPy:
+			frac = fmt.Sprintf("Rational(%v, %v)", sq.Num(), sq.Denom())
+		case Julia:
+			frac = fmt.Sprintf("%v//%v", sq.Num(), sq.Denom())
+		default:
+			frac = fmt.Sprintf("%v/%v", sq.Num(), sq.Denom())
+		}
+	}
+	if l == Mathematica {
+		return str + fmt.Sprintf("Sqrt[%v]", frac)
+	}
+	return str + fmt.Sprintf("sqrt(%v)", frac)
+}
+
+// Tuple formats the elements as a list for Mathematica, e.g., "{1, 1/2}", or a tuple for SymPy and Julia, e.g., "(1, 1//2)".
+func (l CASLanguage) Tuple(elems ...string) string {
+	str := strings.Join(elems, ", ")
+	switch {
+	case l == Mathematica:
+		return "{" + str + "}"
+	case len(elems) == 1:
+		return "(" + str + ",)"
+	}
+	return "(" + str + ")"
+}
+
+// CASWriter writes source code assigning exact values to keys, as an Association in Mathematica and a dictionary in SymPy and Julia.
+// The first failure to write is kept and reported by Flush.
+type CASWriter struct {
+	lang    CASLanguage
+	bw      *bufio.Writer
+	name    string
+	entries int
+	// The entries of the exact signed squares, for Julia.
+	squares strings.Builder
+}
+
+// NewCASWriter returns a writer of source code in the given language to w.
+func NewCASWriter(w io.Writer, lang CASLanguage) *CASWriter {
+	return &CASWriter{lang: lang, bw: bufio.NewWriter(w)}
+}
+
+// Comment writes each line as a comment.
+func (cw *CASWriter) Comment(lines ...string) {
+	for _, line := range lines {
+		if cw.lang == Mathematica {
+			fmt.Fprintf(cw.bw, "(* %v *)\n", line)
+		} else {
+			fmt.Fprintf(cw.bw, "# %v\n", line)
+		}
+	}
+}
+
+// Begin starts the assignment of the mapping to the variable name, importing what its values need.
+// For Julia, End also assigns the exact signed squares sign(c)c² as Rational{BigInt} to name_squares.
+func (cw *CASWriter) Begin(name string) {
+	cw.name = name
+	cw.entries = 0
+	cw.squares.Reset()
+	switch cw.lang {
+	case Mathematica:
+		fmt.Fprintf(cw.bw, "%v = <|", name)
+	case SymPy:
+		fmt.Fprintf(cw.bw, "from sympy import Rational, sqrt\n\n%v = {", name)
+	case Julia:
+		// Keys that are equal numbers hash the same, so looking up (1, 1//2) finds (1//1, 1//2).
+		fmt.Fprintf(cw.bw, "%v = Dict{Any,Float64}(", name)
+	}
+}
+
+// Entry adds a key, formatted with the methods of CASLanguage, and its value.
+func (cw *CASWriter) Entry(key string, value SignedSqrt) {
+	// Mathematica does not allow a trailing comma.
+	if cw.entries > 0 {
+		fmt.Fprint(cw.bw, ",")
+	}
+	cw.entries++
+	op := " => "
+	switch cw.lang {
+	case Mathematica:
+		op = " -> "
+	case SymPy:
+		op = ": "
+	}
+	fmt.Fprintf(cw.bw, "\n    %v%v%v", key, op, cw.lang.SignedSqrt(value))
+	if cw.lang == Julia {
+		if cw.entries > 1 {
+			cw.squares.WriteString(",")
+		}
+		sq := value.SignedSquare()
+		fmt.Fprintf(&cw.squares, "\n    %v => %v//%v", key, sq.Num(), sq.Denom())
+	}
+}
+
+// End ends the assignment started by Begin.
+func (cw *CASWriter) End() {
+	switch cw.lang {
+	case Mathematica:
+		fmt.Fprint(cw.bw, "\n|>;\n")
+	case SymPy:
+		fmt.Fprint(cw.bw, "\n}\n")
+	case Julia:
+		fmt.Fprint(cw.bw, "\n)\n\n")
+		cw.Comment(fmt.Sprintf("Exact signed squares sign(c)c² of the values of %v.", cw.name))
+		fmt.Fprintf(cw.bw, "%v_squares = Dict{Any,Rational{BigInt}}(%v\n)\n", cw.name, cw.squares.String())
+		cw.squares.Reset()
+	}
+}
+
+// Flush writes any buffered code to the underlying writer.
+// Failures are reported as *WriteError.
+func (cw *CASWriter) Flush() error {
+	if err := cw.bw.Flush(); err != nil {
+		return &WriteError{Err: err}
+	}
+	return nil
+}
+
+// WriteCAS writes the table to w as source code of a computer algebra system, assigning the non-zero coefficients
+// to the variable cg keyed by the quantum numbers in the order of the system's own CG function, see CASLanguage.PhaseConvention:
+// {{j1, m1}, {j2, m2}, {j, m}} for Mathematica and (j1, m1, j2, m2, j, m) for SymPy and Julia.
+// Coefficients are in the order of Table.Each. Unknown languages are refused before anything is written,
+// failures to write are reported as *WriteError.
+func (t *Table) WriteCAS(w io.Writer, lang CASLanguage) error {
+	if !lang.valid() {
+		return fmt.Errorf("unknown CAS language %q", lang)
+	}
+	cw := NewCASWriter(w, lang)
+	j1, j2 := lang.HalfInteger(t.J1()), lang.HalfInteger(t.J2())
+	cw.Comment(
+		fmt.Sprintf("Clebsch-Gordan coefficients <j1,m1;j2,m2|j,m> for j1 = %v, j2 = %v.", t.J1(), t.J2()),
+		lang.PhaseConvention(),
+		"Coefficients not listed are zero.",
+	)
+	cw.Begin("cg")
+	t.Each(func(j, m, m1, m2 HalfInteger, c SignedSqrt) bool {
+		if c.IsZero() {
+			return true
+		}
+		var key string
+		if lang == Mathematica {
+			key = lang.Tuple(lang.Tuple(j1, lang.HalfInteger(m1)), lang.Tuple(j2, lang.HalfInteger(m2)), lang.Tuple(lang.HalfInteger(j), lang.HalfInteger(m)))
+		} else {
+			key = lang.Tuple(j1, lang.HalfInteger(m1), j2, lang.HalfInteger(m2), lang.HalfInteger(j), lang.HalfInteger(m))
+		}
+		cw.Entry(key, c)
+		return true
+	})
+	cw.End()
+	return cw.Flush()
+}
@@ -1,9 +1,5 @@
 package cg
 
-import (
-	"math/big"
-)
-
 // Represents a cell in the CG table. Each cell is identified by the the state |j,m>.
 // Each cell contains a set of non-vanishing CG coefficients, which corresponds different combinations of
 // <m1,m2|j,m>, where m1+m2=m.
@@ -11,18 +7,25 @@ type cell struct {
 	// The range of the m1 value (doubled so we store only integers).
 	minTwom1 int
 	maxTwom1 int
-	// Indices correspond to decreasing m1 value.
-	c []*big.Rat
+	// The coefficients are kept in the slots of the column from off on, in order of decreasing m1.
+	col *column
+	off int
 }
 
-func newCell(minTwom1, maxTwom1 int) *cell {
+func newCell(col *column, off, minTwom1, maxTwom1 int) *cell {
 	return &cell{
 		minTwom1: minTwom1,
 		maxTwom1: maxTwom1,
-		c:        make([]*big.Rat, (maxTwom1-minTwom1)/2+1),
+		col:      col,
+		off:      off,
 	}
 }
 
+// Returns the number of coefficients of this cell.
+func (c *cell) size() int {
+	return (c.maxTwom1-c.minTwom1)/2 + 1
+}
+
 // Given index to the coefficient slice, returns the corresponding 2m1 value.
 func (c *cell) twom1ForIndex(idx int) int {
 	return c.maxTwom1 - 2*idx
@@ -34,6 +37,16 @@ func (c *cell) isGoodTwom1(twom1 int) bool {
 }
 
 // Gets the coefficient for the given 2m1 value.
-func (c *cell) get(twom1 int) *big.Rat {
-	return c.c[(c.maxTwom1-twom1)/2]
+func (c *cell) get(twom1 int) radical {
+	return c.at((c.maxTwom1 - twom1) / 2)
+}
+
+// Gets the coefficient at the given index.
+func (c *cell) at(idx int) radical {
+	return c.col.load(c.off + idx)
+}
+
+// Sets the coefficient at the given index.
+func (c *cell) set(idx int, v radical) {
+	c.col.save(c.off+idx, v)
 }
@@ -1,9 +1,7 @@
 package cg
 
 import (
-	"fmt"
 	"math/big"
-	"sync"
 )
 
 // A column of states, indexed by dj, for all states |j1+j2-dj,m>.
@@ -12,10 +10,22 @@ type column struct {
 	dj    int
 	twoj  int
 	cells []*cell
-	wg    sync.WaitGroup
+	// Slots of the coefficients of all cells, top cell first, see store for the encoding.
+	n []int64
+	d []int64
+	s []uint64
 }
 
 func newColumn(t *Table, dj int) *column {
+	col, slots := newColumnCells(t, dj)
+	col.n = make([]int64, slots)
+	col.d = make([]int64, slots)
+	col.s = make([]uint64, slots*t.store.radicandWords)
+	return col
+}
+
+// Lays out the cells of column dj and returns it with the number of slots they take, without allocating the slots.
+func newColumnCells(t *Table, dj int) (*column, int) {
 	cellCount := (t.twoj1+t.twoj2)/2 + 1 - dj
 	col := &column{
 		t:     t,
@@ -24,6 +34,7 @@ func newColumn(t *Table, dj int) *column {
 		cells: make([]*cell, cellCount),
 	}
 
+	slots := 0
 	for i := 0; i < cellCount; i++ {
 		// Determine the m1 range:
 		// * -j1 <= m1 <= j1
@@ -38,18 +49,19 @@ func newColumn(t *Table, dj int) *column {
 		if max > twom+t.twoj2 {
 			max = twom + t.twoj2
 		}
-		col.cells[i] = newCell(min, max)
+		col.cells[i] = newCell(col, slots, min, max)
+		slots += (max-min)/2 + 1
 	}
-	// Top cell of this column depends on its row peers before.
-	col.wg.Add(dj)
-	return col
+	return col, slots
 }
 
-func (col *column) compute() {
-	// Wait for all dependency of the top cell of this column to be ready.
-	col.wg.Wait()
-	col.computeTop()
+// Rungs narrower than this many coefficients per chunk are lowered serially.
+// A variable so the benchmarks can compare grain sizes, see BenchmarkComputeCG.
+var lowerGrain = 32
 
+// Computes cell i+1 of this column from cell i by applying the lowering operator.
+// The coefficients of cell i+1 only depend on cell i, so wide rungs are spread across idle workers of the pool, which may be nil.
+func (col *column) lower(i int, pool *Pool) error {
 	// Go down the ladder by applying lowering operator.
 	// Cross-referencing to group-nut pp225 eq (18), here is the mapping:
 	// * j=j1+j2-dj;
@@ -66,106 +78,78 @@ func (col *column) compute() {
 	//
 	//
 
+	return pool.parallelFor(col.cells[i+1].size(), lowerGrain, func(lo, hi int) error {
+		return col.lowerRange(i, lo, hi)
+	})
+}
+
+// Computes coefficients lo to hi-1 of cell i+1 of this column from cell i.
+func (col *column) lowerRange(i, lo, hi int) error {
 	// All relevant values are scaled by 2 so we deal only with integers.
-	for i := 0; i < len(col.cells)-1; i++ {
-		twom := col.twoj - 2*i
-		current, lower := col.cells[i], col.cells[i+1]
-		for l := range lower.c {
-			twom1 := lower.twom1ForIndex(l)
-			twom2 := twom - 2 - twom1
-			lower.c[l] = big.NewRat(0, 1)
-			// Contribution from m1+1 term in current.
-			if current.isGoodTwom1(twom1 + 2) {
-				// √((j1+1+m1)(j1-m1))
-				r := big.NewRat(int64((col.t.twoj1+2+twom1)*(col.t.twoj1-twom1)), 4)
-				accum(lower.c[l], r.Mul(r, current.get(twom1+2)))
-			}
-			// Contribution from m1 term in current.
-			if current.isGoodTwom1(twom1) {
-				// √((j2+1+m2)(j2-m2))
-				r := big.NewRat(int64((col.t.twoj2+2+twom2)*(col.t.twoj2-twom2)), 4)
-				accum(lower.c[l], r.Mul(r, current.get(twom1)))
+	twom := col.twoj - 2*i
+	current, lower := col.cells[i], col.cells[i+1]
+	for l := lo; l < hi; l++ {
+		twom1 := lower.twom1ForIndex(l)
+		twom2 := twom - 2 - twom1
+		var sum radical
+		// Contribution from m1+1 term in current.
+		if current.isGoodTwom1(twom1 + 2) {
+			// √((j1+1+m1)(j1-m1))
+			v := current.get(twom1+2).mulSqrt((col.t.twoj1+2+twom1)/2, (col.t.twoj1-twom1)/2)
+			if err := accum(&sum, v); err != nil {
+				return err
 			}
-			// 1/√((j+1-m)(j+m))
-			r := big.NewRat(4, int64((col.twoj+2-twom)*(col.twoj+twom)))
-			lower.c[l].Mul(lower.c[l], r)
 		}
-		// Unblock one dependency of the last column of the row.
-		if col.dj+i+1 < len(col.t.columns) {
-			col.t.columns[col.dj+i+1].wg.Done()
+		// Contribution from m1 term in current.
+		if current.isGoodTwom1(twom1) {
+			// √((j2+1+m2)(j2-m2))
+			v := current.get(twom1).mulSqrt((col.t.twoj2+2+twom2)/2, (col.t.twoj2-twom2)/2)
+			if err := accum(&sum, v); err != nil {
+				return err
+			}
 		}
+		// 1/√((j+1-m)(j+m))
+		lower.set(l, sum.quoSqrt((col.twoj+2-twom)/2, (col.twoj+twom)/2))
 	}
+	return nil
 }
 
-func (col *column) computeTop() {
+func (col *column) computeTop() error {
 	topCell := col.cells[0]
-	topCell.c[0] = big.NewRat(1, 1)
+	one := radical{r: ratFromInt(1)}
 	if col.dj == 0 {
 		// Init case.
-		return
+		topCell.set(0, one)
+		return nil
 	}
 
 	rowPeer := func(dj int) *cell { return col.t.cell(dj, col.dj) }
 
-	c0 := topCell.c[0]
 	// Normalization constraint for 0th coefficient (one corresponding to the max m1), sign is positive by convention, see Shankar (15.2.10).
+	c0sq := big.NewRat(1, 1)
 	for dj := 0; dj < col.dj; dj++ {
-		c0.Sub(c0, BlankRat().Abs(rowPeer(dj).c[0]))
+		c0sq.Sub(c0sq, rowPeer(dj).at(0).square())
 	}
-	if c0.Sign() <= 0 {
-		panic(fmt.Sprintf("non-postive sign for square of coefficient <m1,m2| at column %v", col.dj))
+	if c0sq.Sign() <= 0 {
+		return &OrthonormalityError{Column: col.dj, Square: c0sq}
 	}
+	c0, err := radicalFromSignedSquare(c0sq, col.t.maxPrime())
+	if err != nil {
+		return err
+	}
+	topCell.set(0, c0)
 
 	// The remaining coefficients in topCell.
 	// Calculated using the orthogonality constraint between lth and 0th.
 	for l := 1; l <= col.dj; l++ {
-		topCell.c[l] = big.NewRat(0, 1)
-		cl := topCell.c[l]
+		var cl radical
 		for dj := 0; dj < col.dj; dj++ {
 			peer := rowPeer(dj)
-			accum(cl, BlankRat().Mul(peer.c[0], peer.c[l]))
+			if err := accum(&cl, peer.at(0).mul(peer.at(l))); err != nil {
+				return err
+			}
 		}
-		cl.Quo(cl, c0).Neg(cl)
-	}
-}
-
-// Accumulates v onto sum (both are to be interpreted as square of the underlying rational values with sign on the numerator).
-func accum(sum, v *big.Rat) {
-	// Determine the overall sign.
-	n1 := BlankInt().Mul(sum.Num(), v.Denom())
-	n2 := BlankInt().Mul(sum.Denom(), v.Num())
-	overallSign := n1.Add(n1, n2).Sign()
-
-	if overallSign == 0 {
-		sum.SetFrac64(0, 1)
-		return
-	}
-
-	abs1 := BlankRat().Abs(sum)
-	abs2 := BlankRat().Abs(v)
-
-	// Cross term.
-	cross := BlankRat().Mul(abs1, abs2)
-	// Verify cross term is perfect square of rational.
-	// Note this is a much stronger condition than the condition that CG coefficients themselves are rational squares.
-	numRoot := BlankInt().Sqrt(cross.Num())
-	r := BlankInt().Mul(numRoot, numRoot)
-	if r.Cmp(cross.Num()) != 0 {
-		panic(fmt.Sprintf("numerator of cross term (%v, %v) is not square", sum, v))
-	}
-	denomRoot := BlankInt().Sqrt(cross.Denom())
-	r = r.Mul(denomRoot, denomRoot)
-	if r.Cmp(cross.Denom()) != 0 {
-		panic(fmt.Sprintf("denominator of cross term (%v, %v) is not square", sum, v))
-	}
-	cross = cross.SetFrac(numRoot, denomRoot)
-	crossFactor := big.NewRat(2*int64(sum.Num().Sign()*v.Num().Sign()), 1)
-
-	// Add to sum the sum of squares and cross term with proper sign and factor 2.
-	sum.Add(abs1, abs2).Add(sum, cross.Mul(cross, crossFactor))
-
-	// Apply the overall sign.
-	if overallSign < 0 {
-		sum.Neg(sum)
+		topCell.set(l, cl.quo(c0).neg())
 	}
+	return nil
 }
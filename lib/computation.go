@@ -0,0 +1,159 @@
+package cg
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+)
+
+// Holds the state of an in-progress table computation that is shared between the column tasks.
+type computation struct {
+	ctx  context.Context
+	t    *Table
+	pool *Pool
+	// Top cell of each column depends on its row peers before, pending[dj] counts the ones not yet computed.
+	// A column is queued on the pool once its count drops to zero.
+	pending []int32
+	// Counts the columns not yet finished.
+	wg sync.WaitGroup
+
+	mu  sync.Mutex
+	err error
+
+	progress *progress
+}
+
+// Counts computed cells across one or more computations and reports them.
+type progress struct {
+	mu    sync.Mutex
+	fn    func(done, total int)
+	done  int
+	total int
+}
+
+func newProgress(fn func(done, total int)) *progress {
+	return &progress{fn: fn}
+}
+
+// Adds cells to be computed to the total.
+func (p *progress) add(cells int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.total += cells
+}
+
+// Reports one more computed cell.
+func (p *progress) step() {
+	if p.fn == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.done++
+	p.fn(p.done, p.total)
+}
+
+// Queues the columns of t on the pool as their dependencies are satisfied, see wait for the result.
+// The cells of t must have been added to prog beforehand.
+func startComputation(ctx context.Context, t *Table, pool *Pool, prog *progress) *computation {
+	c := &computation{
+		ctx:      ctx,
+		t:        t,
+		pool:     pool,
+		pending:  make([]int32, len(t.columns)),
+		progress: prog,
+	}
+	for dj := range t.columns {
+		c.pending[dj] = int32(dj)
+	}
+	c.wg.Add(len(t.columns))
+	// Only the first column is ready initially, the others follow through unblock.
+	c.schedule(t.columns[0])
+	return c
+}
+
+// Waits for all columns to finish and returns the first error encountered.
+func (c *computation) wait() error {
+	c.wg.Wait()
+	return c.failure()
+}
+
+// Queues the column on the pool, or gives it up if the pool is closed.
+func (c *computation) schedule(col *column) {
+	err := c.pool.submit(func() {
+		defer c.wg.Done()
+		c.run(col)
+	})
+	if err != nil {
+		c.fail(err)
+		c.release(col, 0)
+		c.wg.Done()
+	}
+}
+
+// Records the first error encountered by any column.
+func (c *computation) fail(err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.err == nil {
+		c.err = err
+	}
+}
+
+func (c *computation) failure() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.err
+}
+
+// Reports whether the columns should go on, they give up early if the context is done or another column failed.
+func (c *computation) proceed() bool {
+	if err := c.ctx.Err(); err != nil {
+		c.fail(err)
+	}
+	return c.failure() == nil
+}
+
+// Unblocks the columns depending on cells i, i+1, ... of the given column, so they never wait on a column that gave up.
+func (c *computation) release(col *column, i int) {
+	for ; i < len(col.cells); i++ {
+		c.unblock(col, i)
+	}
+}
+
+// Unblocks the column whose top cell is the row peer of cell i of the given column, queuing it if this was its last dependency.
+func (c *computation) unblock(col *column, i int) {
+	if i > 0 && col.dj+i < len(c.pending) {
+		if atomic.AddInt32(&c.pending[col.dj+i], -1) == 0 {
+			c.schedule(c.t.columns[col.dj+i])
+		}
+	}
+}
+
+// Computes all cells of the given column, top to bottom. All its dependencies must be satisfied.
+func (c *computation) run(col *column) {
+	if !c.proceed() {
+		c.release(col, 0)
+		return
+	}
+	if err := col.computeTop(); err != nil {
+		c.fail(err)
+		c.release(col, 0)
+		return
+	}
+	c.progress.step()
+	for i := 0; i < len(col.cells)-1; i++ {
+		if !c.proceed() {
+			c.release(col, i+1)
+			return
+		}
+		if err := col.lower(i, c.pool); err != nil {
+			c.fail(err)
+			c.release(col, i+1)
+			return
+		}
+		c.progress.step()
+		// Unblock one dependency of the last column of the row.
+		c.unblock(col, i+1)
+	}
+}
@@ -0,0 +1,125 @@
+package cg
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// CSVColumn names a column of the rows written by Table.WriteCSV.
+type CSVColumn string
+
+const (
+	CSVJ1 CSVColumn = "j1"
+	CSVJ2 CSVColumn = "j2"
+	CSVJ  CSVColumn = "j"
+	CSVM  CSVColumn = "m"
+	CSVM1 CSVColumn = "m1"
+	CSVM2 CSVColumn = "m2"
+	// The signed square of the coefficient as an exact fraction, e.g., "-2/5", see Table.Query.
+	CSVSquare CSVColumn = "square"
+	// The coefficient in simplified radical form, e.g., "-√10/5", see SignedSqrt.String.
+	CSVRadical CSVColumn = "radical"
+	// The coefficient as the shortest decimal that reads back as the same float64.
+	CSVDecimal CSVColumn = "decimal"
+)
+
+// AllCSVColumns lists all columns in their default order.
+var AllCSVColumns = []CSVColumn{CSVJ1, CSVJ2, CSVJ, CSVM, CSVM1, CSVM2, CSVSquare, CSVRadical, CSVDecimal}
+
+// ParseCSVColumns parses a comma-separated list of column names such as "j,m,m1,m2,decimal".
+func ParseCSVColumns(str string) ([]CSVColumn, error) {
+	var columns []CSVColumn
+	for _, name := range strings.Split(str, ",") {
+		col := CSVColumn(strings.TrimSpace(name))
+		if !col.valid() {
+			return nil, fmt.Errorf("unknown column %q, want some of %v", col, AllCSVColumns)
+		}
+		columns = append(columns, col)
+	}
+	return columns, nil
+}
+
+func (c CSVColumn) valid() bool {
+	for _, col := range AllCSVColumns {
+		if c == col {
+			return true
+		}
+	}
+	return false
+}
+
+// CSVOptions configures Table.WriteCSV.
+type CSVOptions struct {
+	// Columns to write in this order, all of AllCSVColumns if empty.
+	Columns []CSVColumn
+	// Field delimiter, ',' if zero. Use '\t' for TSV.
+	Comma rune
+}
+
+// WriteCSV writes the table to w as CSV (or TSV), with a header row naming the columns and then one row per non-zero coefficient
+// ⟨j1,m1;j2,m2|j,m⟩, in order of decreasing j, then decreasing m, then decreasing m1. Options may be nil.
+// Unknown columns are refused before anything is written, failures to write are reported as *WriteError.
+func (t *Table) WriteCSV(w io.Writer, opts *CSVOptions) error {
+	if opts == nil {
+		opts = &CSVOptions{}
+	}
+	columns := opts.Columns
+	if len(columns) == 0 {
+		columns = AllCSVColumns
+	}
+	for _, col := range columns {
+		if !col.valid() {
+			return fmt.Errorf("unknown CSV column %q", col)
+		}
+	}
+	cw := csv.NewWriter(w)
+	if opts.Comma != 0 {
+		cw.Comma = opts.Comma
+	}
+	record := make([]string, len(columns))
+	for i, col := range columns {
+		record[i] = string(col)
+	}
+	err := cw.Write(record)
+	j1, j2 := t.J1().String(), t.J2().String()
+	t.Each(func(j, m, m1, m2 HalfInteger, c SignedSqrt) bool {
+		if c.IsZero() {
+			return true
+		}
+		for i, col := range columns {
+			switch col {
+			case CSVJ1:
+				record[i] = j1
+			case CSVJ2:
+				record[i] = j2
+			case CSVJ:
+				record[i] = j.String()
+			case CSVM:
+				record[i] = m.String()
+			case CSVM1:
+				record[i] = m1.String()
+			case CSVM2:
+				record[i] = m2.String()
+			case CSVSquare:
+				record[i] = c.SignedSquare().RatString()
+			case CSVRadical:
+				record[i] = c.String()
+			case CSVDecimal:
+				record[i] = strconv.FormatFloat(c.Float64(), 'g', -1, 64)
+			}
+		}
+		err = cw.Write(record)
+		return err == nil
+	})
+	if err == nil {
+		cw.Flush()
+		err = cw.Error()
+	}
+	if err != nil {
+		return &WriteError{Err: err}
+	}
+	return nil
+}
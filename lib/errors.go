@@ -0,0 +1,113 @@
+package cg
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// ErrPoolClosed is returned when a computation is started on a Pool that has been closed, or the pool is closed while it runs.
+var ErrPoolClosed = errors.New("pool is closed")
+
+// InvalidJError is returned when j1 or j2 is not a valid angular momentum value.
+type InvalidJError struct {
+	// Twice the value of the offending j1 and j2.
+	TwoJ1 int
+	TwoJ2 int
+}
+
+func (e *InvalidJError) Error() string {
+	return fmt.Sprintf("invalid j1 or j2: %v, %v", FormatHalfInteger(e.TwoJ1), FormatHalfInteger(e.TwoJ2))
+}
+
+// OrthonormalityError is returned when the normalization constraint of a column's top cell cannot be satisfied.
+// This indicates a broken invariant of the ladder computation rather than bad input.
+type OrthonormalityError struct {
+	// Column index dj, i.e., the column of states with j=j1+j2-dj.
+	Column int
+	// The offending signed square of the top coefficient.
+	Square *big.Rat
+}
+
+func (e *OrthonormalityError) Error() string {
+	return fmt.Sprintf("non-positive sign for square of coefficient <m1,m2| at column %v: %v", e.Column, FormatRat(e.Square))
+}
+
+// NonSquareCrossTermError is returned when two signed squares cannot be added because their cross term is not the square of a rational.
+type NonSquareCrossTermError struct {
+	// The two signed squares being added.
+	Sum *big.Rat
+	V   *big.Rat
+	// Either "numerator" or "denominator", whichever failed the perfect square check,
+	// or "radicand" when the square-free parts of the two coefficients differ.
+	Part string
+}
+
+func (e *NonSquareCrossTermError) Error() string {
+	return fmt.Sprintf("%v of cross term (%v, %v) is not square", e.Part, e.Sum, e.V)
+}
+
+// RadicandError is returned when a signed square cannot be put in radical form r√s,
+// because its square-free part has prime factors beyond those possible for the coefficients of the table.
+type RadicandError struct {
+	Square *big.Rat
+	// Largest prime factor allowed.
+	MaxPrime int
+}
+
+func (e *RadicandError) Error() string {
+	return fmt.Sprintf("square-free part of %v has prime factors larger than %v", FormatRat(e.Square), e.MaxPrime)
+}
+
+// WriteError is returned when rendered output cannot be written.
+type WriteError struct {
+	Err error
+}
+
+func (e *WriteError) Error() string {
+	return fmt.Sprintf("failed to write output: %v", e.Err)
+}
+
+func (e *WriteError) Unwrap() error { return e.Err }
+
+// TriangleError is returned when the requested total angular momentum j does not appear in the coupling of j1 and j2.
+type TriangleError struct {
+	// Twice the value of j1, j2 and j.
+	TwoJ1 int
+	TwoJ2 int
+	TwoJ  int
+}
+
+func (e *TriangleError) Error() string {
+	return fmt.Sprintf("j = %v does not appear in the coupling of j1 = %v and j2 = %v",
+		FormatHalfInteger(e.TwoJ), FormatHalfInteger(e.TwoJ1), FormatHalfInteger(e.TwoJ2))
+}
+
+// MemoryLimitError is returned when the estimated memory of a table exceeds the configured limit.
+type MemoryLimitError struct {
+	// Estimated and allowed memory in bytes.
+	Estimate int64
+	Limit    int64
+}
+
+func (e *MemoryLimitError) Error() string {
+	return fmt.Sprintf("estimated memory %v bytes exceeds the limit of %v bytes", e.Estimate, e.Limit)
+}
+
+// TableDataError is returned when serialized table data is malformed or does not describe a valid CG table.
+type TableDataError struct {
+	// The serialization format, e.g. "json".
+	Format string
+	Reason string
+	// The underlying error, if any.
+	Err error
+}
+
+func (e *TableDataError) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("invalid %v table data: %v: %v", e.Format, e.Reason, e.Err)
+	}
+	return fmt.Sprintf("invalid %v table data: %v", e.Format, e.Reason)
+}
+
+func (e *TableDataError) Unwrap() error { return e.Err }
@@ -0,0 +1,227 @@
+package cg
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// HalfInteger is an integer or half-odd-integer value, such as an angular momentum j or its z-projection m.
+// It stores twice the value so arithmetic stays exact; the zero value is 0.
+type HalfInteger struct {
+	twice int
+}
+
+// NewHalfInteger returns the half integer whose value is twice/2.
+func NewHalfInteger(twice int) HalfInteger {
+	return HalfInteger{twice: twice}
+}
+
+// HalfIntegerFromInt returns the half integer whose value is the whole number n.
+func HalfIntegerFromInt(n int) HalfInteger {
+	return HalfInteger{twice: 2 * n}
+}
+
+// Twice returns twice the value, which is always an integer.
+func (h HalfInteger) Twice() int { return h.twice }
+
+// Float64 returns the value as a float64.
+func (h HalfInteger) Float64() float64 { return float64(h.twice) / 2 }
+
+// Add returns h+o.
+func (h HalfInteger) Add(o HalfInteger) HalfInteger { return HalfInteger{twice: h.twice + o.twice} }
+
+// Sub returns h-o.
+func (h HalfInteger) Sub(o HalfInteger) HalfInteger { return HalfInteger{twice: h.twice - o.twice} }
+
+// Neg returns -h.
+func (h HalfInteger) Neg() HalfInteger { return HalfInteger{twice: -h.twice} }
+
+// Abs returns |h|.
+func (h HalfInteger) Abs() HalfInteger {
+	if h.twice < 0 {
+		return h.Neg()
+	}
+	return h
+}
+
+// Cmp compares h and o and returns -1, 0 or +1.
+func (h HalfInteger) Cmp(o HalfInteger) int {
+	switch {
+	case h.twice < o.twice:
+		return -1
+	case h.twice > o.twice:
+		return 1
+	}
+	return 0
+}
+
+// IsInteger reports whether h is a whole number.
+func (h HalfInteger) IsInteger() bool { return h.twice%2 == 0 }
+
+// IsHalfOdd reports whether h is a half-odd-integer, i.e., 1/2, 3/2, ...
+func (h HalfInteger) IsHalfOdd() bool { return h.twice%2 != 0 }
+
+// SameParity reports whether h and o are both integers or both half-odd-integers, i.e., whether h-o is a whole number.
+func (h HalfInteger) SameParity(o HalfInteger) bool { return (h.twice-o.twice)%2 == 0 }
+
+// IsProjection reports whether m is a valid z-projection of angular momentum j, i.e., -j <= m <= j with j-m integer.
+func IsProjection(j, m HalfInteger) bool {
+	return j.twice >= 0 && m.twice >= -j.twice && m.twice <= j.twice && j.SameParity(m)
+}
+
+// Triangle reports whether j1, j2 and j satisfy the triangle rule |j1-j2| <= j <= j1+j2 with j1+j2+j integer,
+// i.e., whether j appears in the coupling of j1 and j2.
+func Triangle(j1, j2, j HalfInteger) bool {
+	if j1.twice < 0 || j2.twice < 0 {
+		return false
+	}
+	lo, hi := CouplingRange(j1, j2)
+	return j.Cmp(lo) >= 0 && j.Cmp(hi) <= 0 && j.SameParity(hi)
+}
+
+// CouplingRange returns the minimum |j1-j2| and maximum j1+j2 of the total angular momentum from coupling j1 and j2.
+func CouplingRange(j1, j2 HalfInteger) (lo, hi HalfInteger) {
+	return j1.Sub(j2).Abs(), j1.Add(j2)
+}
+
+// String formats h as plain text, e.g., "3", "-7/2".
+func (h HalfInteger) String() string {
+	if h.IsInteger() {
+		return strconv.Itoa(h.twice / 2)
+	}
+	return fmt.Sprintf("%v/2", h.twice)
+}
+
+// LaTeX formats h for LaTeX math mode, e.g., "3", "-\frac{7}{2}".
+func (h HalfInteger) LaTeX() string {
+	str := ""
+	twice := h.twice
+	if twice < 0 {
+		str += "-"
+		twice = -twice
+	}
+	if twice%2 == 0 {
+		return str + strconv.Itoa(twice/2)
+	}
+	return str + fmt.Sprintf("\\frac{%v}{2}", twice)
+}
+
+// Unicode formats h using Unicode fraction characters, e.g., "3", "½", "−⁷⁄₂".
+func (h HalfInteger) Unicode() string {
+	str := ""
+	twice := h.twice
+	if twice < 0 {
+		str += "−"
+		twice = -twice
+	}
+	if twice%2 == 0 {
+		return str + strconv.Itoa(twice/2)
+	}
+	if twice == 1 {
+		return str + "½"
+	}
+	return str + mapDigits(strconv.Itoa(twice), superscriptDigits) + "⁄" + string(subscriptDigits[2])
+}
+
+var (
+	superscriptDigits = []rune("⁰¹²³⁴⁵⁶⁷⁸⁹")
+	subscriptDigits   = []rune("₀₁₂₃₄₅₆₇₈₉")
+)
+
+func mapDigits(ascii string, digits []rune) string {
+	var b strings.Builder
+	for _, r := range ascii {
+		b.WriteRune(digits[r-'0'])
+	}
+	return b.String()
+}
+
+// Converts superscript and subscript digits to their ASCII counterparts.
+func normalizeDigits(str string) string {
+	return strings.Map(func(r rune) rune {
+		for d := range superscriptDigits {
+			if r == superscriptDigits[d] || r == subscriptDigits[d] {
+				return rune('0' + d)
+			}
+		}
+		return r
+	}, str)
+}
+
+// ParseHalfIntegerValue parses a half integer from its string representation.
+// Accepted forms include "3", "-7/2", "1.5", "½", "1½" and "³⁄₂", with an optional sign.
+func ParseHalfIntegerValue(str string) (HalfInteger, error) {
+	s := strings.TrimSpace(str)
+	neg := false
+	for _, sign := range []string{"-", "−", "+"} {
+		if strings.HasPrefix(s, sign) {
+			neg = sign != "+"
+			s = s[len(sign):]
+			break
+		}
+	}
+	twice, err := parseUnsignedTwice(s)
+	if err != nil {
+		return HalfInteger{}, fmt.Errorf("invalid value for half integer: '%v'", str)
+	}
+	if neg {
+		twice = -twice
+	}
+	return HalfInteger{twice: twice}, nil
+}
+
+// Parses an unsigned half integer and returns twice its value.
+func parseUnsignedTwice(s string) (int, error) {
+	atoi := func(digits string) (int, error) {
+		if digits == "" || strings.ContainsAny(digits, "+-") {
+			return 0, strconv.ErrSyntax
+		}
+		return strconv.Atoi(digits)
+	}
+	switch {
+	case strings.HasSuffix(s, "½"):
+		whole := strings.TrimSuffix(s, "½")
+		if whole == "" {
+			return 1, nil
+		}
+		v, err := atoi(whole)
+		return 2*v + 1, err
+	case strings.ContainsAny(s, "/⁄"):
+		parts := strings.FieldsFunc(normalizeDigits(s), func(r rune) bool { return r == '/' || r == '⁄' })
+		if len(parts) != 2 || parts[1] != "2" || strings.Count(s, "/")+strings.Count(s, "⁄") != 1 {
+			return 0, strconv.ErrSyntax
+		}
+		return atoi(parts[0])
+	case strings.Contains(s, "."):
+		parts := strings.SplitN(s, ".", 2)
+		whole := 0
+		if parts[0] != "" {
+			v, err := atoi(parts[0])
+			if err != nil {
+				return 0, err
+			}
+			whole = v
+		}
+		frac := strings.TrimRight(parts[1], "0")
+		switch {
+		case frac == "" && parts[1] != "":
+			return 2 * whole, nil
+		case frac == "5":
+			return 2*whole + 1, nil
+		}
+		return 0, strconv.ErrSyntax
+	}
+	v, err := atoi(s)
+	return 2 * v, err
+}
+
+// Set parses str into h, so that *HalfInteger can be used as a flag.Value.
+func (h *HalfInteger) Set(str string) error {
+	v, err := ParseHalfIntegerValue(str)
+	if err != nil {
+		return err
+	}
+	*h = v
+	return nil
+}
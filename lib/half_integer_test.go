@@ -0,0 +1,75 @@
+package cg
+
+import "testing"
+
+func TestParseHalfIntegerValue(t *testing.T) {
+	for _, tc := range []struct {
+		str   string
+		twice int
+	}{
+		{"0", 0},
+		{"3", 6},
+		{"-7/2", -7},
+		{"−7/2", -7},
+		{"+7/2", 7},
+		{"1.5", 3},
+		{"-0.5", -1},
+		{".5", 1},
+		{"2.0", 4},
+		{"½", 1},
+		{"−½", -1},
+		{"1½", 3},
+		{"³⁄₂", 3},
+		{"−¹¹⁄₂", -11},
+		{" 5/2 ", 5},
+	} {
+		got, err := ParseHalfIntegerValue(tc.str)
+		if err != nil {
+			t.Errorf("ParseHalfIntegerValue(%q): %v", tc.str, err)
+			continue
+		}
+		if got.Twice() != tc.twice {
+			t.Errorf("ParseHalfIntegerValue(%q) = %v, want %v", tc.str, got, NewHalfInteger(tc.twice))
+		}
+	}
+}
+
+func TestParseHalfIntegerValueRejects(t *testing.T) {
+	for _, str := range []string{
+		"", "-", "abc", "1/3", "3/4", "1/2/2", "/2", "1.25", "1.", "--1", "-+1", "1/-2", "1.-5", "x½", "³⁄₃",
+	} {
+		if got, err := ParseHalfIntegerValue(str); err == nil {
+			t.Errorf("ParseHalfIntegerValue(%q) = %v, want an error", str, got)
+		}
+	}
+}
+
+func TestHalfIntegerFormat(t *testing.T) {
+	for _, tc := range []struct {
+		twice               int
+		str, latex, unicode string
+	}{
+		{0, "0", "0", "0"},
+		{6, "3", "3", "3"},
+		{1, "1/2", "\\frac{1}{2}", "½"},
+		{-7, "-7/2", "-\\frac{7}{2}", "−⁷⁄₂"},
+		{-4, "-2", "-2", "−2"},
+	} {
+		h := NewHalfInteger(tc.twice)
+		if got := h.String(); got != tc.str {
+			t.Errorf("String of %v/2 = %q, want %q", tc.twice, got, tc.str)
+		}
+		if got := h.LaTeX(); got != tc.latex {
+			t.Errorf("LaTeX of %v/2 = %q, want %q", tc.twice, got, tc.latex)
+		}
+		if got := h.Unicode(); got != tc.unicode {
+			t.Errorf("Unicode of %v/2 = %q, want %q", tc.twice, got, tc.unicode)
+		}
+		// Every format parses back to h.
+		for _, str := range []string{tc.str, tc.unicode} {
+			if back, err := ParseHalfIntegerValue(str); err != nil || back != h {
+				t.Errorf("ParseHalfIntegerValue(%q) = %v, %v, want %v", str, back, err, h)
+			}
+		}
+	}
+}
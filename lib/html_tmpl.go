@@ -2,13 +2,13 @@ package cg
 
 import (
 	"html/template"
+	"io"
 )
 
-var (
-	rootTmpl *template.Template
-)
+var defaultHTMLRenderer = &HTMLRenderer{Template: template.Must(ParseHTMLTemplate(DefaultHTMLTemplate))}
 
-const rootTmplStr = `<!DOCTYPE html>
+// DefaultHTMLTemplate is the template of WriteHTML, a starting point for own templates, see ParseHTMLTemplate.
+const DefaultHTMLTemplate = `<!DOCTYPE html>
 <html>
 <head>
 <style>
@@ -89,9 +89,24 @@ td.jheading {
 </html>
 `
 
-func init() {
+// ParseHTMLTemplate parses an HTML template that renders the *TableData of a table, see Table.TemplateData.
+// Besides the functions of html/template, the template can use isEven, which tells whether an int is even.
+func ParseHTMLTemplate(text string) (*template.Template, error) {
 	funcMap := template.FuncMap{
 		"isEven": func(n int) bool { return n%2 == 0 },
 	}
-	rootTmpl = template.Must(template.New("root").Funcs(funcMap).Parse(rootTmplStr))
+	return template.New("root").Funcs(funcMap).Parse(text)
+}
+
+// HTMLRenderer renders tables with an HTML template, see ParseHTMLTemplate.
+type HTMLRenderer struct {
+	Template *template.Template
+}
+
+// Render executes the template on the data model of the table, failures are reported as *WriteError.
+func (r *HTMLRenderer) Render(t *Table, w io.Writer) error {
+	if err := r.Template.Execute(w, t.TemplateData()); err != nil {
+		return &WriteError{Err: err}
+	}
+	return nil
 }
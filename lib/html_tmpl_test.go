@@ -0,0 +1,56 @@
+package cg
+
+import (
+	"bytes"
+	"os"
+	"testing"
+)
+
+func TestHTMLTemplateData(t *testing.T) {
+	tmpl, err := ParseHTMLTemplate(`v{{ .Version }} {{ .J1 }}×{{ .J2 }}: {{ range $i, $j := .J }}{{ if $i }}, {{ end }}{{ $j }}{{ end }}
+{{- range .Sections }}{{ range .Rows }}
+{{ .M1 }},{{ .M2 }}:{{ range .Decimals }} {{ printf "%.4f" . }}{{ end }} |{{ range .Radicals }} {{ . }}{{ end }}
+{{- end }}{{ end }}
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	table, err := ComputeCGE(2, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := (&HTMLRenderer{Template: tmpl}).Render(table, &buf); err != nil {
+		t.Fatal(err)
+	}
+	want := `v1 1×1/2: 3/2, 1/2
+1,1/2: 1.0000 | 1
+1,-1/2: 0.5774 0.8165 | √3/3 √6/3
+0,1/2: 0.8165 -0.5774 | √6/3 -√3/3
+-1,1/2: 0.5774 -0.8165 | √3/3 -√6/3
+0,-1/2: 0.8165 0.5774 | √6/3 √3/3
+-1,-1/2: 1.0000 | 1
+`
+	if got := buf.String(); got != want {
+		t.Errorf("got\n%v\nwant\n%v", got, want)
+	}
+}
+
+// Pins the output of DefaultHTMLTemplate, which the tables of earlier releases were rendered with.
+func TestDefaultHTMLTemplate(t *testing.T) {
+	want, err := os.ReadFile("testdata/cg-1-1_2.html")
+	if err != nil {
+		t.Fatal(err)
+	}
+	table, err := ComputeCGE(2, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := table.WriteHTML(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if got := buf.String(); got != string(want) {
+		t.Errorf("WriteHTML of 1×1/2 differs from testdata/cg-1-1_2.html:\n%v", got)
+	}
+}
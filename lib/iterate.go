@@ -0,0 +1,132 @@
+package cg
+
+// Entry is a single coefficient ⟨j1,m1;j2,m2|j,m⟩ of a table.
+type Entry struct {
+	J  HalfInteger
+	M  HalfInteger
+	M1 HalfInteger
+	M2 HalfInteger
+	C  SignedSqrt
+}
+
+// Block is the unitary sub-matrix of a table for a fixed total m.
+// It changes basis from the product states |m1,m2⟩ (rows) to the total angular momentum states |j,m⟩ (columns).
+type Block struct {
+	M HalfInteger
+	// The row labels, in order of decreasing m1.
+	M1 []HalfInteger
+	M2 []HalfInteger
+	// The column labels, in order of decreasing j.
+	J []HalfInteger
+	// C[r][c]=⟨j1,M1[r];j2,M2[r]|J[c],M⟩.
+	C [][]SignedSqrt
+}
+
+// J1 returns j1 in the order used to create this table.
+func (t *Table) J1() HalfInteger {
+	if t.exchanged {
+		return NewHalfInteger(t.twoj2)
+	}
+	return NewHalfInteger(t.twoj1)
+}
+
+// J2 returns j2 in the order used to create this table.
+func (t *Table) J2() HalfInteger {
+	if t.exchanged {
+		return NewHalfInteger(t.twoj1)
+	}
+	return NewHalfInteger(t.twoj2)
+}
+
+// Each calls fn for every coefficient ⟨j1,m1;j2,m2|j,m⟩ allowed by the selection rules, where j1 and j2 are in the order used to create this table.
+// Coefficients are visited in order of decreasing j, then decreasing m, then decreasing m1; accidental zeros are included.
+// Iteration stops when fn returns false.
+func (t *Table) Each(fn func(j, m, m1, m2 HalfInteger, c SignedSqrt) bool) {
+	for dj := 0; dj <= t.twoj2; dj++ {
+		twoj := t.twoj1 + t.twoj2 - 2*dj
+		for twom := twoj; twom >= -twoj; twom -= 2 {
+			if !t.eachInRow(twoj, twom, fn) {
+				return
+			}
+		}
+	}
+}
+
+// Visits the coefficients of state |j,m⟩ in order of decreasing m1, returns false if fn stopped the iteration.
+func (t *Table) eachInRow(twoj, twom int, fn func(j, m, m1, m2 HalfInteger, c SignedSqrt) bool) bool {
+	minTwom1, maxTwom1 := t.twom1Range(twom)
+	for twom1 := maxTwom1; twom1 >= minTwom1; twom1 -= 2 {
+		twom2 := twom - twom1
+		c := t.coefficient(twoj, twom, twom1, twom2)
+		if !fn(NewHalfInteger(twoj), NewHalfInteger(twom), NewHalfInteger(twom1), NewHalfInteger(twom2), c) {
+			return false
+		}
+	}
+	return true
+}
+
+// Block returns the unitary sub-matrix for the given total m, or nil if m is not a valid projection of j1+j2.
+func (t *Table) Block(m HalfInteger) *Block {
+	twojmax := t.twoj1 + t.twoj2
+	twom := m.Twice()
+	if !IsProjection(NewHalfInteger(twojmax), m) {
+		return nil
+	}
+	b := &Block{M: m}
+	for twoj := twojmax; twoj >= t.twoj1-t.twoj2 && twoj >= twom && twoj >= -twom; twoj -= 2 {
+		b.J = append(b.J, NewHalfInteger(twoj))
+	}
+	minTwom1, maxTwom1 := t.twom1Range(twom)
+	for twom1 := maxTwom1; twom1 >= minTwom1; twom1 -= 2 {
+		twom2 := twom - twom1
+		row := make([]SignedSqrt, len(b.J))
+		for i, j := range b.J {
+			row[i] = t.coefficient(j.Twice(), twom, twom1, twom2)
+		}
+		b.M1 = append(b.M1, NewHalfInteger(twom1))
+		b.M2 = append(b.M2, NewHalfInteger(twom2))
+		b.C = append(b.C, row)
+	}
+	return b
+}
+
+// Column returns all coefficients of the multiplet with total angular momentum j, in order of decreasing m, then decreasing m1.
+// It returns nil if j does not appear in the coupling of j1 and j2.
+func (t *Table) Column(j HalfInteger) []Entry {
+	if !Triangle(t.J1(), t.J2(), j) {
+		return nil
+	}
+	var entries []Entry
+	twoj := j.Twice()
+	for twom := twoj; twom >= -twoj; twom -= 2 {
+		t.eachInRow(twoj, twom, func(j, m, m1, m2 HalfInteger, c SignedSqrt) bool {
+			entries = append(entries, Entry{J: j, M: m, M1: m1, M2: m2, C: c})
+			return true
+		})
+	}
+	return entries
+}
+
+// Returns the range of 2m1 for the given 2m, where m1 is in the order used to create this table.
+func (t *Table) twom1Range(twom int) (min, max int) {
+	twoj1, twoj2 := t.J1().Twice(), t.J2().Twice()
+	// Take the tighter bound max(m-j2, -j1) <= m1 <= min(m+j2, j1), as in newColumn.
+	min = -twoj1
+	if min < twom-twoj2 {
+		min = twom - twoj2
+	}
+	max = twoj1
+	if max > twom+twoj2 {
+		max = twom + twoj2
+	}
+	return min, max
+}
+
+// Queries ⟨j1,m1;j2,m2|j,m⟩ like QuerySqrt without an extra copy.
+func (t *Table) coefficient(twoj, twom, twom1, twom2 int) SignedSqrt {
+	r := t.queryHelper(twoj, twom, twom1, twom2, false)
+	if r.Sign() == 0 {
+		return SignedSqrt{}
+	}
+	return SignedSqrt{sq: r}
+}
@@ -0,0 +1,153 @@
+package cg
+
+import (
+	"fmt"
+	"math/big"
+	"testing"
+)
+
+// Pairs of 2j1, 2j2 covering j1 < j2, j1 = j2 and j1 > j2, with zero and half-odd operands.
+var iterateTestPairs = [][2]int{{0, 0}, {0, 3}, {3, 0}, {1, 2}, {2, 1}, {2, 5}, {5, 2}, {4, 4}, {3, 6}}
+
+func TestEachMatchesCoefficient(t *testing.T) {
+	for _, pair := range iterateTestPairs {
+		twoj1, twoj2 := pair[0], pair[1]
+		table, err := ComputeCGE(twoj1, twoj2)
+		if err != nil {
+			t.Fatalf("ComputeCGE(%v, %v): %v", twoj1, twoj2, err)
+		}
+		if table.J1().Twice() != twoj1 || table.J2().Twice() != twoj2 {
+			t.Errorf("table of %v, %v has j1 = %v and j2 = %v", twoj1, twoj2, table.J1(), table.J2())
+		}
+		var prev [3]int
+		count := 0
+		table.Each(func(j, m, m1, m2 HalfInteger, c SignedSqrt) bool {
+			key := [3]int{j.Twice(), m.Twice(), m1.Twice()}
+			if count > 0 && !(key[0] < prev[0] || key[0] == prev[0] && (key[1] < prev[1] || key[1] == prev[1] && key[2] < prev[2])) {
+				t.Errorf("%v×%v: (j, m, m1) = %v visited after %v", table.J1(), table.J2(), key, prev)
+			}
+			prev = key
+			count++
+			if !IsProjection(table.J1(), m1) || !IsProjection(table.J2(), m2) || m1.Add(m2) != m {
+				t.Errorf("%v×%v: visited m = %v, m1 = %v, m2 = %v", table.J1(), table.J2(), m, m1, m2)
+			}
+			want := Coefficient(twoj1, m1.Twice(), twoj2, m2.Twice(), j.Twice(), m.Twice())
+			if c.SignedSquare().Cmp(want) != 0 {
+				t.Errorf("%v×%v: ⟨%v,%v;%v,%v|%v,%v⟩ = %v, want the signed square %v",
+					table.J1(), table.J2(), table.J1(), m1, table.J2(), m2, j, m, c, want.RatString())
+			}
+			return true
+		})
+		// Each state |m1,m2⟩ appears once for each j coupling to its m.
+		want := 0
+		for twom1 := -twoj1; twom1 <= twoj1; twom1 += 2 {
+			for twom2 := -twoj2; twom2 <= twoj2; twom2 += 2 {
+				for twoj := twoj1 + twoj2; twoj >= twoj1-twoj2 && twoj >= twoj2-twoj1; twoj -= 2 {
+					if twoj >= twom1+twom2 && twoj >= -twom1-twom2 {
+						want++
+					}
+				}
+			}
+		}
+		if count != want {
+			t.Errorf("%v×%v: Each visited %v coefficients, want %v", table.J1(), table.J2(), count, want)
+		}
+	}
+}
+
+func TestEachStops(t *testing.T) {
+	table, err := ComputeCGE(2, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	count := 0
+	table.Each(func(j, m, m1, m2 HalfInteger, c SignedSqrt) bool {
+		count++
+		return count < 3
+	})
+	if count != 3 {
+		t.Errorf("Each called fn %v times after it returned false, want 3", count)
+	}
+}
+
+func TestBlockIsUnitary(t *testing.T) {
+	for _, pair := range iterateTestPairs {
+		twoj1, twoj2 := pair[0], pair[1]
+		table, err := ComputeCGE(twoj1, twoj2)
+		if err != nil {
+			t.Fatalf("ComputeCGE(%v, %v): %v", twoj1, twoj2, err)
+		}
+		for twom := twoj1 + twoj2; twom >= -twoj1-twoj2; twom -= 2 {
+			m := NewHalfInteger(twom)
+			b := table.Block(m)
+			name := fmt.Sprintf("%v×%v, m = %v", table.J1(), table.J2(), m)
+			if b == nil || b.M != m {
+				t.Fatalf("%v: Block = %v", name, b)
+			}
+			if len(b.M1) != len(b.J) || len(b.M2) != len(b.J) || len(b.C) != len(b.J) {
+				t.Fatalf("%v: block has %v rows, %v and %v labels and %v columns", name, len(b.C), len(b.M1), len(b.M2), len(b.J))
+			}
+			for r := range b.C {
+				if r > 0 && b.M1[r].Cmp(b.M1[r-1]) >= 0 {
+					t.Errorf("%v: m1 = %v in row %v follows %v", name, b.M1[r], r, b.M1[r-1])
+				}
+				if b.M1[r].Add(b.M2[r]) != m {
+					t.Errorf("%v: row %v has m1 = %v and m2 = %v", name, r, b.M1[r], b.M2[r])
+				}
+				for c := range b.J {
+					if c > 0 && b.J[c].Cmp(b.J[c-1]) >= 0 {
+						t.Errorf("%v: j = %v in column %v follows %v", name, b.J[c], c, b.J[c-1])
+					}
+					if want := table.QuerySqrt(b.J[c].Twice(), twom, b.M1[r].Twice(), b.M2[r].Twice()); b.C[r][c].Cmp(want) != 0 {
+						t.Errorf("%v: C[%v][%v] = %v, QuerySqrt has %v", name, r, c, b.C[r][c], want)
+					}
+				}
+			}
+			// Rows and columns have unit norm; the squares are rational, so their sums are exact.
+			for i := range b.C {
+				rowNorm, colNorm := BlankRat(), BlankRat()
+				for k := range b.C {
+					rowNorm.Add(rowNorm, b.C[i][k].Square())
+					colNorm.Add(colNorm, b.C[k][i].Square())
+				}
+				if rowNorm.Cmp(big.NewRat(1, 1)) != 0 || colNorm.Cmp(big.NewRat(1, 1)) != 0 {
+					t.Errorf("%v: row %v has norm² %v and column %v has norm² %v", name, i, rowNorm.RatString(), i, colNorm.RatString())
+				}
+			}
+		}
+		if b := table.Block(NewHalfInteger(twoj1 + twoj2 + 2)); b != nil {
+			t.Errorf("%v×%v: Block beyond j1+j2 = %v", table.J1(), table.J2(), b)
+		}
+		if b := table.Block(NewHalfInteger(twoj1 + twoj2 - 1)); b != nil {
+			t.Errorf("%v×%v: Block with the wrong parity = %v", table.J1(), table.J2(), b)
+		}
+	}
+}
+
+func TestColumnMatchesEach(t *testing.T) {
+	for _, pair := range iterateTestPairs {
+		twoj1, twoj2 := pair[0], pair[1]
+		table, err := ComputeCGE(twoj1, twoj2)
+		if err != nil {
+			t.Fatalf("ComputeCGE(%v, %v): %v", twoj1, twoj2, err)
+		}
+		entries := make(map[int][]Entry)
+		table.Each(func(j, m, m1, m2 HalfInteger, c SignedSqrt) bool {
+			entries[j.Twice()] = append(entries[j.Twice()], Entry{J: j, M: m, M1: m1, M2: m2, C: c})
+			return true
+		})
+		for twoj := 0; twoj <= twoj1+twoj2+2; twoj++ {
+			j := NewHalfInteger(twoj)
+			got, want := table.Column(j), entries[twoj]
+			if len(got) != len(want) {
+				t.Errorf("%v×%v: Column(%v) has %v entries, want %v", table.J1(), table.J2(), j, len(got), len(want))
+				continue
+			}
+			for i := range got {
+				if got[i].J != want[i].J || got[i].M != want[i].M || got[i].M1 != want[i].M1 || got[i].M2 != want[i].M2 || got[i].C.Cmp(want[i].C) != 0 {
+					t.Errorf("%v×%v: Column(%v)[%v] = %+v, Each visited %+v", table.J1(), table.J2(), j, i, got[i], want[i])
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,267 @@
+package cg
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+)
+
+// JSON form of a table, see Table.MarshalJSON:
+//
+//	{
+//	  "version": 1,
+//	  "j1": "3/2",
+//	  "j2": "1",
+//	  "phase": "condon-shortley",
+//	  "coefficients": [
+//	    {"j": "5/2", "m": "5/2", "m1": "3/2", "m2": "1", "signed_square": "1"},
+//	    {"j": "5/2", "m": "3/2", "m1": "3/2", "m2": "0", "signed_square": "2/5"},
+//	    ...
+//	  ]
+//	}
+//
+// All angular momenta are integers or halves written like "3/2". Each coefficient ⟨j1,m1;j2,m2|j,m⟩ is given by its signed square
+// sign(C)C² as an exact fraction like "-2/5", the value returned by Table.Query.
+// Every coefficient allowed by the selection rules is listed once, including accidental zeros, for all m,
+// in order of decreasing j, then decreasing m, then decreasing m1.
+type tableJSON struct {
+	Version      int               `json:"version"`
+	J1           string            `json:"j1"`
+	J2           string            `json:"j2"`
+	Phase        string            `json:"phase"`
+	Coefficients []coefficientJSON `json:"coefficients"`
+}
+
+type coefficientJSON struct {
+	J            string `json:"j"`
+	M            string `json:"m"`
+	M1           string `json:"m1"`
+	M2           string `json:"m2"`
+	SignedSquare string `json:"signed_square"`
+}
+
+const (
+	jsonVersion = 1
+	// The phase convention of the coefficients, see Shankar (15.2.10).
+	phaseConvention = "condon-shortley"
+)
+
+// MarshalJSON encodes the table with j1 and j2 in the order used to create it and all its coefficients.
+// See the schema documented at tableJSON.
+func (t *Table) MarshalJSON() ([]byte, error) {
+	data := tableJSON{
+		Version: jsonVersion,
+		J1:      t.J1().String(),
+		J2:      t.J2().String(),
+		Phase:   phaseConvention,
+	}
+	t.Each(func(j, m, m1, m2 HalfInteger, c SignedSqrt) bool {
+		data.Coefficients = append(data.Coefficients, coefficientJSON{
+			J:            j.String(),
+			M:            m.String(),
+			M1:           m1.String(),
+			M2:           m2.String(),
+			SignedSquare: c.SignedSquare().RatString(),
+		})
+		return true
+	})
+	return json.Marshal(data)
+}
+
+// UnmarshalJSON decodes a table encoded by MarshalJSON into t.
+// Every coefficient must be listed exactly once and agree with the symmetry of the table,
+// so that the loaded table answers Query like the one encoded.
+// Failures are reported as *TableDataError.
+func (t *Table) UnmarshalJSON(b []byte) error {
+	var data tableJSON
+	if err := json.Unmarshal(b, &data); err != nil {
+		return &TableDataError{Format: "json", Reason: "malformed document", Err: err}
+	}
+	if data.Version != jsonVersion {
+		return &TableDataError{Format: "json", Reason: fmt.Sprintf("unsupported version %v", data.Version)}
+	}
+	if data.Phase != phaseConvention {
+		return &TableDataError{Format: "json", Reason: fmt.Sprintf("unsupported phase convention %q", data.Phase)}
+	}
+	j1, err := ParseHalfIntegerValue(data.J1)
+	if err != nil {
+		return &TableDataError{Format: "json", Reason: "bad j1", Err: err}
+	}
+	j2, err := ParseHalfIntegerValue(data.J2)
+	if err != nil {
+		return &TableDataError{Format: "json", Reason: "bad j2", Err: err}
+	}
+	if j1.Twice() < 0 || j2.Twice() < 0 {
+		return &TableDataError{Format: "json", Reason: "negative j1 or j2"}
+	}
+	twoj1, twoj2 := j1.Twice(), j2.Twice()
+	exchanged := twoj1 < twoj2
+	if exchanged {
+		twoj1, twoj2 = twoj2, twoj1
+	}
+	if twoj1+twoj2+1 >= factorLimit {
+		return &TableDataError{Format: "json", Reason: fmt.Sprintf("j1 = %v and j2 = %v out of range", j1, j2)}
+	}
+	// The document lists every coefficient for all m, more than the table stores for m >= 0.
+	// Refusing tables larger than that keeps a short document from allocating a huge one.
+	if !storesAtMost(twoj1, twoj2, int64(len(data.Coefficients))) {
+		return &TableDataError{Format: "json", Reason: fmt.Sprintf("%v coefficients are too few for j1 = %v and j2 = %v", len(data.Coefficients), j1, j2)}
+	}
+	loaded := newTable(twoj1, twoj2)
+	loaded.exchanged = exchanged
+
+	// Flags of each stored slot, indexed by dj and slot, so that every coefficient is listed exactly once.
+	seen := make([][]uint8, len(loaded.columns))
+	for dj, col := range loaded.columns {
+		seen[dj] = make([]uint8, len(col.n))
+	}
+	entries := make([]Entry, len(data.Coefficients))
+	squares := make([]*big.Rat, len(data.Coefficients))
+	for i, c := range data.Coefficients {
+		if entries[i], squares[i], err = parseCoefficientJSON(c); err != nil {
+			return err
+		}
+		e := entries[i]
+		if !Triangle(j1, j2, e.J) || !IsProjection(e.J, e.M) || !IsProjection(j1, e.M1) || !IsProjection(j2, e.M2) || e.M1.Add(e.M2) != e.M {
+			return &TableDataError{Format: "json", Reason: fmt.Sprintf("no coefficient ⟨%v,%v;%v,%v|%v,%v⟩", j1, e.M1, j2, e.M2, e.J, e.M)}
+		}
+		// The table only stores m >= 0, a coefficient for m < 0 marks the slot of its mirror ⟨-m1,-m2|j,-m⟩
+		// and is checked against it below.
+		flag := slotSet
+		twom, twom1, twom2 := e.M.Twice(), e.M1.Twice(), e.M2.Twice()
+		if twom < 0 {
+			flag = slotMirrored
+			twom, twom1, twom2 = -twom, -twom1, -twom2
+		}
+		cell, idx := loaded.slot(e.J.Twice(), twom, twom1, twom2)
+		s := &seen[cell.col.dj][cell.off+idx]
+		if *s&flag != 0 {
+			return &TableDataError{Format: "json", Reason: fmt.Sprintf("coefficient ⟨%v,%v;%v,%v|%v,%v⟩ listed twice", j1, e.M1, j2, e.M2, e.J, e.M)}
+		}
+		*s |= flag
+		if flag == slotSet {
+			if err := loaded.setCoefficient(cell, idx, squares[i]); err != nil {
+				return err
+			}
+		}
+	}
+	if err := loaded.checkSlots(seen); err != nil {
+		return err
+	}
+	for i, e := range entries {
+		if loaded.QueryHalf(e.J, e.M, e.M1, e.M2).Cmp(squares[i]) != 0 {
+			return &TableDataError{Format: "json", Reason: fmt.Sprintf("coefficient ⟨%v,%v;%v,%v|%v,%v⟩ contradicts the symmetry of the table", j1, e.M1, j2, e.M2, e.J, e.M)}
+		}
+	}
+	*t = *loaded
+	return nil
+}
+
+func parseCoefficientJSON(c coefficientJSON) (Entry, *big.Rat, error) {
+	var e Entry
+	for _, f := range []struct {
+		name string
+		str  string
+		v    *HalfInteger
+	}{{"j", c.J, &e.J}, {"m", c.M, &e.M}, {"m1", c.M1, &e.M1}, {"m2", c.M2, &e.M2}} {
+		v, err := ParseHalfIntegerValue(f.str)
+		if err != nil {
+			return e, nil, &TableDataError{Format: "json", Reason: "bad " + f.name, Err: err}
+		}
+		*f.v = v
+	}
+	sq, ok := BlankRat().SetString(c.SignedSquare)
+	if !ok {
+		return e, nil, &TableDataError{Format: "json", Reason: fmt.Sprintf("bad signed square %q", c.SignedSquare)}
+	}
+	return e, sq, nil
+}
+
+// Flags of the stored slots seen while loading a table.
+const (
+	// The coefficient of the slot is listed.
+	slotSet uint8 = 1 << iota
+	// The coefficient ⟨-m1,-m2|j,-m⟩ mirroring the slot is listed.
+	slotMirrored
+)
+
+// Returns the cell and index of the stored coefficient ⟨j1,m1;j2,m2|j,m⟩, for m >= 0 and j1, j2 in the order used to create this table.
+func (t *Table) slot(twoj, twom, twom1, twom2 int) (*cell, int) {
+	if t.exchanged {
+		twom1 = twom2
+	}
+	cell := t.cell((t.twoj1+t.twoj2-twoj)/2, (t.twoj1+t.twoj2-twom)/2)
+	return cell, (cell.maxTwom1 - twom1) / 2
+}
+
+// Stores the coefficient at index idx of the cell given by its signed square in the order used to create this table.
+func (t *Table) setCoefficient(cell *cell, idx int, sq *big.Rat) error {
+	c, err := radicalFromSignedSquare(sq, t.maxPrime())
+	if err != nil {
+		return &TableDataError{Format: "json", Reason: "bad signed square", Err: err}
+	}
+	// See queryHelper for the symmetry of the exchange.
+	if t.exchanged && cell.col.dj%2 != 0 {
+		c = c.neg()
+	}
+	cell.set(idx, c)
+	return nil
+}
+
+// Checks that every stored slot was set, and mirrored unless m = 0, so that no coefficient is missing from the document.
+func (t *Table) checkSlots(seen [][]uint8) error {
+	for dj, col := range t.columns {
+		for i, cell := range col.cells {
+			twom := col.twoj - 2*i
+			for idx := 0; idx < cell.size(); idx++ {
+				want := slotSet
+				if twom > 0 {
+					want |= slotMirrored
+				}
+				missing := want &^ seen[dj][cell.off+idx]
+				if missing == 0 {
+					continue
+				}
+				twom1 := cell.twom1ForIndex(idx)
+				twom2 := twom - twom1
+				if t.exchanged {
+					twom1, twom2 = twom2, twom1
+				}
+				if missing&slotSet == 0 {
+					twom, twom1, twom2 = -twom, -twom1, -twom2
+				}
+				return &TableDataError{Format: "json", Reason: fmt.Sprintf("coefficient ⟨%v,%v;%v,%v|%v,%v⟩ missing",
+					t.J1(), NewHalfInteger(twom1), t.J2(), NewHalfInteger(twom2), NewHalfInteger(col.twoj), NewHalfInteger(twom))}
+			}
+		}
+	}
+	return nil
+}
+
+// WriteJSON writes the table to w in the form of MarshalJSON.
+// Failures are reported as *WriteError.
+func (t *Table) WriteJSON(w io.Writer) error {
+	b, err := t.MarshalJSON()
+	if err == nil {
+		_, err = w.Write(append(b, '\n'))
+	}
+	if err != nil {
+		return &WriteError{Err: err}
+	}
+	return nil
+}
+
+// ReadJSON reads a table written by WriteJSON.
+// Failures are reported as *TableDataError.
+func ReadJSON(r io.Reader) (*Table, error) {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return nil, &TableDataError{Format: "json", Reason: "read failed", Err: err}
+	}
+	t := &Table{}
+	if err := t.UnmarshalJSON(b); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
@@ -0,0 +1,125 @@
+package cg
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestJSONRoundTrip(t *testing.T) {
+	for twoj1 := 0; twoj1 <= 8; twoj1++ {
+		for twoj2 := 0; twoj2 <= 8; twoj2++ {
+			table := ComputeCG(twoj1, twoj2)
+			var buf bytes.Buffer
+			if err := table.WriteJSON(&buf); err != nil {
+				t.Fatal(err)
+			}
+			loaded, err := ReadJSON(&buf)
+			if err != nil {
+				t.Fatalf("ReadJSON of the table for 2j1 = %v, 2j2 = %v: %v", twoj1, twoj2, err)
+			}
+			table.Each(func(j, m, m1, m2 HalfInteger, c SignedSqrt) bool {
+				if got := loaded.QueryHalf(j, m, m1, m2); got.Cmp(c.SignedSquare()) != 0 {
+					t.Errorf("loaded ⟨%v,%v;%v,%v|%v,%v⟩ = %v, want %v", table.J1(), m1, table.J2(), m2, j, m, got.RatString(), c.SignedSquare().RatString())
+				}
+				return true
+			})
+		}
+	}
+}
+
+// A short document must not make the decoder allocate a huge table.
+func TestReadJSONRejectsLargeJ(t *testing.T) {
+	for _, doc := range []string{
+		`{"version":1,"j1":"100000","j2":"1","phase":"condon-shortley","coefficients":[]}`,
+		`{"version":1,"j1":"1000","j2":"1000","phase":"condon-shortley","coefficients":[]}`,
+	} {
+		var dataErr *TableDataError
+		if _, err := ReadJSON(strings.NewReader(doc)); !errors.As(err, &dataErr) {
+			t.Errorf("ReadJSON(%v) returned %v, want *TableDataError", doc, err)
+		}
+	}
+}
+
+// Returns the JSON document of the table for 2j1, 2j2 with its coefficients passed through edit.
+func editedJSON(t *testing.T, twoj1, twoj2 int, edit func(cs []coefficientJSON) []coefficientJSON) string {
+	t.Helper()
+	b, err := ComputeCG(twoj1, twoj2).MarshalJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var data tableJSON
+	if err := json.Unmarshal(b, &data); err != nil {
+		t.Fatal(err)
+	}
+	data.Coefficients = edit(data.Coefficients)
+	if b, err = json.Marshal(data); err != nil {
+		t.Fatal(err)
+	}
+	return string(b)
+}
+
+// Returns the index of the coefficient ⟨m1,m2|j,m⟩ in cs.
+func findCoefficient(t *testing.T, cs []coefficientJSON, j, m, m1, m2 string) int {
+	t.Helper()
+	for i, c := range cs {
+		if c.J == j && c.M == m && c.M1 == m1 && c.M2 == m2 {
+			return i
+		}
+	}
+	t.Fatalf("no coefficient ⟨%v,%v|%v,%v⟩", m1, m2, j, m)
+	return -1
+}
+
+func TestReadJSONRejectsIncomplete(t *testing.T) {
+	for _, tc := range []struct {
+		name         string
+		twoj1, twoj2 int
+		j, m, m1, m2 string
+	}{
+		{"m > 0", 3, 2, "3/2", "1/2", "1/2", "0"},
+		{"m < 0", 3, 2, "3/2", "-1/2", "-1/2", "0"},
+		{"m = 0", 2, 2, "2", "0", "1", "-1"},
+		{"accidental zero", 2, 2, "1", "0", "0", "0"},
+		{"exchanged", 2, 3, "5/2", "3/2", "1", "1/2"},
+	} {
+		doc := editedJSON(t, tc.twoj1, tc.twoj2, func(cs []coefficientJSON) []coefficientJSON {
+			i := findCoefficient(t, cs, tc.j, tc.m, tc.m1, tc.m2)
+			return append(cs[:i], cs[i+1:]...)
+		})
+		var dataErr *TableDataError
+		if _, err := ReadJSON(strings.NewReader(doc)); !errors.As(err, &dataErr) || !strings.Contains(err.Error(), "missing") {
+			t.Errorf("ReadJSON without the coefficient (%v) returned %v, want a *TableDataError for the missing coefficient", tc.name, err)
+		}
+	}
+}
+
+func TestReadJSONRejectsDuplicates(t *testing.T) {
+	for _, tc := range []struct {
+		name         string
+		twoj1, twoj2 int
+		j, m, m1, m2 string
+		// Whether the duplicate replaces the last coefficient, keeping the count of the document.
+		replace bool
+	}{
+		{"m > 0", 3, 2, "3/2", "1/2", "1/2", "0", false},
+		{"m < 0", 3, 2, "3/2", "-1/2", "-1/2", "0", false},
+		{"in place of another", 3, 2, "5/2", "5/2", "3/2", "1", true},
+		{"exchanged", 2, 3, "1/2", "-1/2", "0", "-1/2", true},
+	} {
+		doc := editedJSON(t, tc.twoj1, tc.twoj2, func(cs []coefficientJSON) []coefficientJSON {
+			dup := cs[findCoefficient(t, cs, tc.j, tc.m, tc.m1, tc.m2)]
+			if tc.replace {
+				cs[len(cs)-1] = dup
+				return cs
+			}
+			return append(cs, dup)
+		})
+		var dataErr *TableDataError
+		if _, err := ReadJSON(strings.NewReader(doc)); !errors.As(err, &dataErr) || !strings.Contains(err.Error(), "twice") {
+			t.Errorf("ReadJSON with a duplicated coefficient (%v) returned %v, want a *TableDataError for the duplicate", tc.name, err)
+		}
+	}
+}
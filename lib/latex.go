@@ -0,0 +1,106 @@
+package cg
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Blocks of m with more rows than this are not kept on one page, see WriteLaTeXFragment.
+const maxUnbrokenLaTeXRows = 30
+
+// WriteLaTeX renders the table as a standalone LaTeX document to w, ready for pdflatex.
+// Failures are reported as *WriteError.
+func (t *Table) WriteLaTeX(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprint(bw, `\documentclass{article}
+\usepackage[margin=2cm]{geometry}
+\usepackage{amsmath}
+\usepackage{longtable}
+\usepackage{multirow}
+\begin{document}
+`)
+	t.writeLaTeXTable(bw)
+	fmt.Fprint(bw, "\\end{document}\n")
+	if err := bw.Flush(); err != nil {
+		return &WriteError{Err: err}
+	}
+	return nil
+}
+
+// WriteLaTeXFragment renders the table as a longtable environment to w, to be included in a document that uses the
+// amsmath, longtable and multirow packages. The layout is that of WriteHTML: the coefficients of each m are a block of
+// rows with one column per j, and each entry is the coefficient ⟨j1,m1;j2,m2|j,m⟩ written as ±√(p/q).
+// Long tables break across pages between blocks of m.
+// Failures are reported as *WriteError.
+func (t *Table) WriteLaTeXFragment(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	t.writeLaTeXTable(bw)
+	if err := bw.Flush(); err != nil {
+		return &WriteError{Err: err}
+	}
+	return nil
+}
+
+// Writes the longtable environment, errors are kept by bw.
+func (t *Table) writeLaTeXTable(bw *bufio.Writer) {
+	j1, j2 := t.J1(), t.J2()
+	js := make([]string, len(t.columns))
+	for dj := range t.columns {
+		js[dj] = fmt.Sprintf("$j=%v$", NewHalfInteger(t.twoj1+t.twoj2-2*dj).LaTeX())
+	}
+	heading := "$m$ & $m_1$ & $m_2$ & " + strings.Join(js, " & ") + ` \\`
+
+	fmt.Fprintf(bw, "\\begin{longtable}{ccc|%v}\n", strings.Repeat("c", len(js)))
+	fmt.Fprintf(bw, "\\caption{Clebsch-Gordan coefficients $\\langle j_1,m_1;j_2,m_2|j,m\\rangle$ for $j_1=%v$, $j_2=%v$}\\\\\n", j1.LaTeX(), j2.LaTeX())
+	fmt.Fprintf(bw, "\\hline\n%v\n\\hline\n\\endfirsthead\n", heading)
+	fmt.Fprintf(bw, "\\hline\n%v\n\\hline\n\\endhead\n", heading)
+	fmt.Fprint(bw, "\\hline\n\\endfoot\n")
+	for _, sec := range t.getSectionsData() {
+		// A multirow m spilling over a page break would be drawn over the foot, so blocks too long to keep together
+		// name their m on the first row instead.
+		unbroken := len(sec.Rows) <= maxUnbrokenLaTeXRows
+		for rowIdx, row := range sec.Rows {
+			m := ""
+			if rowIdx == 0 {
+				m = fmt.Sprintf("$%v$", sec.m.LaTeX())
+				if unbroken && len(sec.Rows) > 1 {
+					m = fmt.Sprintf("\\multirow{%v}{*}{%v}", len(sec.Rows), m)
+				}
+			}
+			cells := make([]string, len(js))
+			for dj, c := range row.coeffs {
+				cells[dj] = fmt.Sprintf("$%v$", sqrtLaTeX(c))
+			}
+			end := `\\`
+			if rowIdx == len(sec.Rows)-1 {
+				end += "\n\\hline"
+			} else if unbroken {
+				// No page break within the block.
+				end += "*"
+			}
+			fmt.Fprintf(bw, "%v & $%v$ & $%v$ & %v %v\n", m, row.m1.LaTeX(), row.m2.LaTeX(), strings.Join(cells, " & "), end)
+		}
+	}
+	fmt.Fprint(bw, "\\end{longtable}\n")
+}
+
+// Formats c for LaTeX math mode as the signed square root of a fraction, e.g., "-\sqrt{\frac{2}{5}}", "\sqrt{3}", "1".
+func sqrtLaTeX(c SignedSqrt) string {
+	if c.IsZero() {
+		return "0"
+	}
+	str := ""
+	if c.Sign() < 0 {
+		str = "-"
+	}
+	sq := c.Square()
+	switch {
+	case sq.IsInt() && isOne(sq.Num()):
+		return str + "1"
+	case sq.IsInt():
+		return str + fmt.Sprintf("\\sqrt{%v}", sq.Num())
+	}
+	return str + fmt.Sprintf("\\sqrt{\\frac{%v}{%v}}", sq.Num(), sq.Denom())
+}
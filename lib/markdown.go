@@ -0,0 +1,45 @@
+package cg
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// WriteMarkdown renders the table as a GitHub-flavored Markdown document to w, for pages that render $...$ math.
+// Like WriteHTML, the coefficients are in blocks of m, each a table with a row per m1, m2 and a column per j,
+// and each entry is the coefficient ⟨j1,m1;j2,m2|j,m⟩ written as ±√(p/q) in inline math.
+// Failures are reported as *WriteError.
+func (t *Table) WriteMarkdown(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "# Clebsch-Gordan coefficients for $j_1=%v$, $j_2=%v$\n", t.J1().LaTeX(), t.J2().LaTeX())
+	for _, sec := range t.getSectionsData() {
+		fmt.Fprintf(bw, "\n## $m=%v$\n\n", sec.m.LaTeX())
+		// All rows of a block have the same columns.
+		cells := []string{"$m_1$", "$m_2$"}
+		for dj := range sec.Rows[0].coeffs {
+			cells = append(cells, fmt.Sprintf("$j=%v$", NewHalfInteger(t.twoj1+t.twoj2-2*dj).LaTeX()))
+		}
+		writeMarkdownRow(bw, cells)
+		for i := range cells {
+			cells[i] = "---"
+		}
+		writeMarkdownRow(bw, cells)
+		for _, row := range sec.Rows {
+			cells = append(cells[:0], fmt.Sprintf("$%v$", row.m1.LaTeX()), fmt.Sprintf("$%v$", row.m2.LaTeX()))
+			for _, c := range row.coeffs {
+				cells = append(cells, fmt.Sprintf("$%v$", sqrtLaTeX(c)))
+			}
+			writeMarkdownRow(bw, cells)
+		}
+	}
+	if err := bw.Flush(); err != nil {
+		return &WriteError{Err: err}
+	}
+	return nil
+}
+
+func writeMarkdownRow(bw *bufio.Writer, cells []string) {
+	fmt.Fprintf(bw, "| %v |\n", strings.Join(cells, " | "))
+}
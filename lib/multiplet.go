@@ -0,0 +1,87 @@
+package cg
+
+import (
+	"math/big"
+)
+
+// Multiplet holds the CG coefficients of the single total angular momentum multiplet |j,m⟩, m=-j,...,j, from coupling j1 and j2.
+type Multiplet struct {
+	// A table with only the column of this multiplet computed.
+	t    *Table
+	twoj int
+}
+
+// ComputeMultiplet computes the CG coefficients ⟨j1,m1;j2,m2|j,m⟩ for a single j, independently of the other multiplets of the table.
+// Arguments are twice the value of actual j1, j2 and j so they are integers.
+// The error is one of *InvalidJError, *TriangleError, *RadicandError or *NonSquareCrossTermError.
+func ComputeMultiplet(twoj1, twoj2, twoj int) (*Multiplet, error) {
+	if twoj1 < 0 || twoj2 < 0 {
+		return nil, &InvalidJError{TwoJ1: twoj1, TwoJ2: twoj2}
+	}
+	if !Triangle(NewHalfInteger(twoj1), NewHalfInteger(twoj2), NewHalfInteger(twoj)) {
+		return nil, &TriangleError{TwoJ1: twoj1, TwoJ2: twoj2, TwoJ: twoj}
+	}
+	exchanged := false
+	if twoj1 < twoj2 {
+		twoj1, twoj2 = twoj2, twoj1
+		exchanged = true
+	}
+	t := &Table{
+		exchanged: exchanged,
+		twoj1:     twoj1,
+		twoj2:     twoj2,
+		columns:   make([]*column, twoj2+1),
+	}
+	t.store = newStore(t.maxPrime())
+	col := newColumn(t, (twoj1+twoj2-twoj)/2)
+	t.columns[col.dj] = col
+
+	// The top cell comes from the closed form instead of the orthonormality constraints with the other columns,
+	// then we go down the ladder as usual.
+	if err := col.computeTopClosedForm(); err != nil {
+		return nil, err
+	}
+	for i := 0; i < len(col.cells)-1; i++ {
+		if err := col.lower(i, nil); err != nil {
+			return nil, err
+		}
+	}
+	return &Multiplet{t: t, twoj: twoj}, nil
+}
+
+// Computes the highest weight state |j,j⟩ of this column with the Racah formula.
+func (col *column) computeTopClosedForm() error {
+	topCell := col.cells[0]
+	for l := 0; l < topCell.size(); l++ {
+		twom1 := topCell.twom1ForIndex(l)
+		c, err := radicalFromSignedSquare(Coefficient(col.t.twoj1, twom1, col.t.twoj2, col.twoj-twom1, col.twoj, col.twoj), col.t.maxPrime())
+		if err != nil {
+			return err
+		}
+		topCell.set(l, c)
+	}
+	return nil
+}
+
+// J returns the total angular momentum of this multiplet.
+func (mu *Multiplet) J() HalfInteger {
+	return NewHalfInteger(mu.twoj)
+}
+
+// Query queries the multiplet for the value ⟨j1,m1;j2,m2|j,m⟩, like Table.Query.
+// It returns zero for any j other than the one of this multiplet.
+func (mu *Multiplet) Query(twoj, twom, twom1, twom2 int) *big.Rat {
+	return mu.t.Query(twoj, twom, twom1, twom2)
+}
+
+// ExchangedQuery queries the multiplet for the value ⟨j2,m2;j1,m1|j,m⟩, like Table.ExchangedQuery.
+// It returns zero for any j other than the one of this multiplet.
+func (mu *Multiplet) ExchangedQuery(twoj, twom, twom1, twom2 int) *big.Rat {
+	return mu.t.ExchangedQuery(twoj, twom, twom1, twom2)
+}
+
+// QuerySqrt queries the multiplet for the value ⟨j1,m1;j2,m2|j,m⟩, like Table.QuerySqrt.
+// It returns zero for any j other than the one of this multiplet.
+func (mu *Multiplet) QuerySqrt(twoj, twom, twom1, twom2 int) SignedSqrt {
+	return mu.t.QuerySqrt(twoj, twom, twom1, twom2)
+}
@@ -0,0 +1,65 @@
+package cg
+
+import (
+	"errors"
+	"testing"
+)
+
+// Compares every multiplet with the column of the full table for all j1, j2 <= 3, including j1 < j2.
+func TestMultipletMatchesTable(t *testing.T) {
+	const maxTwoj = 6
+	for twoj1 := 0; twoj1 <= maxTwoj; twoj1++ {
+		for twoj2 := 0; twoj2 <= maxTwoj; twoj2++ {
+			table, err := ComputeCGE(twoj1, twoj2)
+			if err != nil {
+				t.Fatalf("ComputeCGE(%v, %v): %v", twoj1, twoj2, err)
+			}
+			lo, hi := CouplingRange(NewHalfInteger(twoj1), NewHalfInteger(twoj2))
+			for twoj := lo.Twice(); twoj <= hi.Twice(); twoj += 2 {
+				mu, err := ComputeMultiplet(twoj1, twoj2, twoj)
+				if err != nil {
+					t.Fatalf("ComputeMultiplet(%v, %v, %v): %v", twoj1, twoj2, twoj, err)
+				}
+				if mu.J().Twice() != twoj {
+					t.Errorf("ComputeMultiplet(%v, %v, %v) has j = %v", twoj1, twoj2, twoj, mu.J())
+				}
+				for twom := -twoj; twom <= twoj; twom += 2 {
+					for twom1 := -twoj1; twom1 <= twoj1; twom1 += 2 {
+						twom2 := twom - twom1
+						if got, want := mu.Query(twoj, twom, twom1, twom2), table.Query(twoj, twom, twom1, twom2); got.Cmp(want) != 0 {
+							t.Errorf("multiplet %v of %v, %v: Query(%v, %v, %v, %v) = %v, the table has %v",
+								twoj, twoj1, twoj2, twoj, twom, twom1, twom2, got.RatString(), want.RatString())
+						}
+						if got, want := mu.ExchangedQuery(twoj, twom, twom2, twom1), table.ExchangedQuery(twoj, twom, twom2, twom1); got.Cmp(want) != 0 {
+							t.Errorf("multiplet %v of %v, %v: ExchangedQuery(%v, %v, %v, %v) = %v, the table has %v",
+								twoj, twoj1, twoj2, twoj, twom, twom2, twom1, got.RatString(), want.RatString())
+						}
+						if got, want := mu.QuerySqrt(twoj, twom, twom1, twom2), table.QuerySqrt(twoj, twom, twom1, twom2); got.Cmp(want) != 0 {
+							t.Errorf("multiplet %v of %v, %v: QuerySqrt(%v, %v, %v, %v) = %v, the table has %v",
+								twoj, twoj1, twoj2, twoj, twom, twom1, twom2, got, want)
+						}
+					}
+				}
+				// Other multiplets of the table read as zero.
+				for other := lo.Twice(); other <= hi.Twice(); other += 2 {
+					if other != twoj && mu.Query(other, other, twoj1, other-twoj1).Sign() != 0 {
+						t.Errorf("multiplet %v of %v, %v has a coefficient for j = %v", twoj, twoj1, twoj2, other)
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestComputeMultipletRejects(t *testing.T) {
+	var triangleErr *TriangleError
+	for _, tc := range [][3]int{{2, 2, 6}, {4, 1, 1}, {1, 1, 1}, {2, 2, -2}} {
+		if _, err := ComputeMultiplet(tc[0], tc[1], tc[2]); !errors.As(err, &triangleErr) {
+			t.Errorf("ComputeMultiplet(%v, %v, %v): got %v, want a *TriangleError", tc[0], tc[1], tc[2], err)
+		}
+	}
+	var invalidErr *InvalidJError
+	if _, err := ComputeMultiplet(-1, 1, 0); !errors.As(err, &invalidErr) {
+		t.Errorf("ComputeMultiplet(-1, 1, 0): got %v, want an *InvalidJError", err)
+	}
+}
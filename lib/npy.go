@@ -0,0 +1,192 @@
+package cg
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+	"time"
+)
+
+// NumPy arrays of a table, see WriteNPY and WriteNPZ. All arrays are float64, quantum numbers are stored by value, e.g., 1.5 for 3/2.
+//
+//	cg            dense array [j, m, m1, m2] of the coefficients ⟨j1,m1;j2,m2|j,m⟩, zero where not allowed by the selection rules
+//	j, m, m1, m2  index arrays of the values along each axis of cg, all in increasing order
+//	j1, j2        scalars, in the order used to create the table
+//	unitary       the orthogonal matrix [(m1,m2), (j,m)] changing basis from product states to total angular momentum states,
+//	              block diagonal by decreasing m, within a block rows by decreasing m1 and columns by decreasing j as in Block
+//	unitary_rows  [(m1,m2), 2] pairs m1, m2 of the rows of unitary
+//	unitary_cols  [(j,m), 2] pairs j, m of the columns of unitary
+const npyMagic = "\x93NUMPY"
+
+// The earliest time of the zip format, stored as the modification time of the arrays in archives.
+var zipEpoch = time.Date(1980, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// Writes float64 arrays in the .npy format, version 1.0, keeping the first error.
+type npyWriter struct {
+	bw  *bufio.Writer
+	buf [8]byte
+}
+
+// Starts a little-endian float64 array of the given shape in C order, a scalar if the shape is empty.
+func newNPYWriter(w io.Writer, shape ...int) *npyWriter {
+	dims := make([]string, len(shape))
+	for i, n := range shape {
+		dims[i] = fmt.Sprint(n)
+	}
+	tuple := strings.Join(dims, ", ")
+	if len(shape) == 1 {
+		tuple += ","
+	}
+	header := fmt.Sprintf("{'descr': '<f8', 'fortran_order': False, 'shape': (%v), }", tuple)
+	// Magic, version and header length take 10 bytes, the header is padded with spaces so the data is 64-byte aligned.
+	header += strings.Repeat(" ", 63-(10+len(header))%64) + "\n"
+	nw := &npyWriter{bw: bufio.NewWriter(w)}
+	nw.bw.WriteString(npyMagic)
+	nw.bw.Write([]byte{1, 0})
+	binary.LittleEndian.PutUint16(nw.buf[:], uint16(len(header)))
+	nw.bw.Write(nw.buf[:2])
+	nw.bw.WriteString(header)
+	return nw
+}
+
+func (nw *npyWriter) float(v float64) {
+	binary.LittleEndian.PutUint64(nw.buf[:], math.Float64bits(v))
+	nw.bw.Write(nw.buf[:])
+}
+
+func (nw *npyWriter) halfInteger(h HalfInteger) {
+	nw.float(h.Float64())
+}
+
+func (nw *npyWriter) zeros(n int) {
+	for i := 0; i < n; i++ {
+		nw.float(0)
+	}
+}
+
+func (nw *npyWriter) flush() error {
+	return nw.bw.Flush()
+}
+
+// WriteNPY writes the coefficients to w as the dense array cg described above in the .npy format, for numpy.load.
+// Failures are reported as *WriteError.
+func (t *Table) WriteNPY(w io.Writer) error {
+	if err := t.writeDenseNPY(w); err != nil {
+		return &WriteError{Err: err}
+	}
+	return nil
+}
+
+// WriteNPZ writes all arrays described above to w as a compressed .npz archive, for numpy.load.
+// Failures are reported as *WriteError.
+func (t *Table) WriteNPZ(w io.Writer) error {
+	zw := zip.NewWriter(w)
+	arrays := []struct {
+		name  string
+		write func(w io.Writer) error
+	}{
+		{"cg", t.writeDenseNPY},
+		{"j", func(w io.Writer) error { return writeRangeNPY(w, t.twoj1-t.twoj2, t.twoj1+t.twoj2) }},
+		{"m", func(w io.Writer) error { return writeRangeNPY(w, -t.twoj1-t.twoj2, t.twoj1+t.twoj2) }},
+		{"m1", func(w io.Writer) error { return writeRangeNPY(w, -t.J1().Twice(), t.J1().Twice()) }},
+		{"m2", func(w io.Writer) error { return writeRangeNPY(w, -t.J2().Twice(), t.J2().Twice()) }},
+		{"j1", func(w io.Writer) error { return writeScalarNPY(w, t.J1()) }},
+		{"j2", func(w io.Writer) error { return writeScalarNPY(w, t.J2()) }},
+		{"unitary", t.writeUnitaryNPY},
+		{"unitary_rows", func(w io.Writer) error { return t.writeUnitaryLabelsNPY(w, false) }},
+		{"unitary_cols", func(w io.Writer) error { return t.writeUnitaryLabelsNPY(w, true) }},
+	}
+	for _, a := range arrays {
+		// Fixed times keep archives of the same table identical.
+		f, err := zw.CreateHeader(&zip.FileHeader{Name: a.name + ".npy", Method: zip.Deflate, Modified: zipEpoch})
+		if err != nil {
+			return &WriteError{Err: err}
+		}
+		if err := a.write(f); err != nil {
+			return &WriteError{Err: err}
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return &WriteError{Err: err}
+	}
+	return nil
+}
+
+func (t *Table) writeDenseNPY(w io.Writer) error {
+	twoj1, twoj2 := t.J1().Twice(), t.J2().Twice()
+	twojmin, twojmax := t.twoj1-t.twoj2, t.twoj1+t.twoj2
+	nw := newNPYWriter(w, t.twoj2+1, twojmax+1, twoj1+1, twoj2+1)
+	for twoj := twojmin; twoj <= twojmax; twoj += 2 {
+		for twom := -twojmax; twom <= twojmax; twom += 2 {
+			for twom1 := -twoj1; twom1 <= twoj1; twom1 += 2 {
+				for twom2 := -twoj2; twom2 <= twoj2; twom2 += 2 {
+					if twom1+twom2 != twom || twom > twoj || twom < -twoj {
+						nw.float(0)
+						continue
+					}
+					nw.float(t.coefficient(twoj, twom, twom1, twom2).Float64())
+				}
+			}
+		}
+	}
+	return nw.flush()
+}
+
+func (t *Table) writeUnitaryNPY(w io.Writer) error {
+	n := (t.twoj1 + 1) * (t.twoj2 + 1)
+	nw := newNPYWriter(w, n, n)
+	// Columns of the blocks before the current one.
+	before := 0
+	for twom := t.twoj1 + t.twoj2; twom >= -t.twoj1-t.twoj2; twom -= 2 {
+		b := t.Block(NewHalfInteger(twom))
+		for _, row := range b.C {
+			nw.zeros(before)
+			for _, c := range row {
+				nw.float(c.Float64())
+			}
+			nw.zeros(n - before - len(row))
+		}
+		before += len(b.J)
+	}
+	return nw.flush()
+}
+
+// Writes the pairs m1, m2 of the rows of the unitary matrix, or the pairs j, m of its columns.
+func (t *Table) writeUnitaryLabelsNPY(w io.Writer, cols bool) error {
+	n := (t.twoj1 + 1) * (t.twoj2 + 1)
+	nw := newNPYWriter(w, n, 2)
+	for twom := t.twoj1 + t.twoj2; twom >= -t.twoj1-t.twoj2; twom -= 2 {
+		b := t.Block(NewHalfInteger(twom))
+		if cols {
+			for _, j := range b.J {
+				nw.halfInteger(j)
+				nw.halfInteger(b.M)
+			}
+			continue
+		}
+		for i := range b.M1 {
+			nw.halfInteger(b.M1[i])
+			nw.halfInteger(b.M2[i])
+		}
+	}
+	return nw.flush()
+}
+
+// Writes the values from min to max in steps of one, given twice.
+func writeRangeNPY(w io.Writer, twomin, twomax int) error {
+	nw := newNPYWriter(w, (twomax-twomin)/2+1)
+	for twov := twomin; twov <= twomax; twov += 2 {
+		nw.halfInteger(NewHalfInteger(twov))
+	}
+	return nw.flush()
+}
+
+func writeScalarNPY(w io.Writer, h HalfInteger) error {
+	nw := newNPYWriter(w)
+	nw.halfInteger(h)
+	return nw.flush()
+}
@@ -0,0 +1,160 @@
+package cg
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/binary"
+	"io"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+var npyHeaderPattern = regexp.MustCompile(`^\{'descr': '<f8', 'fortran_order': False, 'shape': \(([^)]*)\), \} *\n$`)
+
+// Parses a float64 array in the .npy format, checking its header.
+func parseNPY(t *testing.T, b []byte) (shape []int, data []float64) {
+	t.Helper()
+	if len(b) < 10 || string(b[:6]) != npyMagic || b[6] != 1 || b[7] != 0 {
+		t.Fatalf("no .npy 1.0 magic in %q", b)
+	}
+	n := 10 + int(binary.LittleEndian.Uint16(b[8:]))
+	if n%64 != 0 || len(b) < n {
+		t.Fatalf(".npy data starts at %v of %v bytes, want a multiple of 64", n, len(b))
+	}
+	m := npyHeaderPattern.FindStringSubmatch(string(b[10:n]))
+	if m == nil {
+		t.Fatalf("bad .npy header %q", b[10:n])
+	}
+	// Python tuples: "()", "(3,)" and "(2, 3)".
+	size := 1
+	if dims := m[1]; dims != "" {
+		if !strings.Contains(dims, ",") {
+			t.Fatalf("shape (%v) is not a tuple", dims)
+		}
+		for _, dim := range strings.Split(strings.TrimSuffix(dims, ","), ", ") {
+			v, err := strconv.Atoi(dim)
+			if err != nil {
+				t.Fatalf("bad shape (%v): %v", dims, err)
+			}
+			shape = append(shape, v)
+			size *= v
+		}
+	}
+	if len(b)-n != 8*size {
+		t.Fatalf(".npy of shape %v has %v data bytes, want %v", shape, len(b)-n, 8*size)
+	}
+	for i := n; i < len(b); i += 8 {
+		data = append(data, math.Float64frombits(binary.LittleEndian.Uint64(b[i:])))
+	}
+	return shape, data
+}
+
+func TestNPYHeader(t *testing.T) {
+	// Dimensions of several widths vary the padding of the header.
+	for _, shape := range [][]int{{}, {1}, {3}, {0}, {2, 3}, {2, 1, 4, 3}, {123456, 0}, {0, 7, 100000}} {
+		var buf bytes.Buffer
+		nw := newNPYWriter(&buf, shape...)
+		size := 1
+		for _, n := range shape {
+			size *= n
+		}
+		for i := 0; i < size; i++ {
+			nw.float(float64(i) / 2)
+		}
+		if err := nw.flush(); err != nil {
+			t.Fatal(err)
+		}
+		got, data := parseNPY(t, buf.Bytes())
+		if len(got) != len(shape) {
+			t.Errorf("shape %v read back as %v", shape, got)
+			continue
+		}
+		for i := range got {
+			if got[i] != shape[i] {
+				t.Errorf("shape %v read back as %v", shape, got)
+			}
+		}
+		for i, v := range data {
+			if v != float64(i)/2 {
+				t.Errorf("element %v of shape %v read back as %v", i, shape, v)
+			}
+		}
+	}
+}
+
+// Reads all arrays of a .npz archive.
+func parseNPZ(t *testing.T, b []byte) map[string][]float64 {
+	t.Helper()
+	zr, err := zip.NewReader(bytes.NewReader(b), int64(len(b)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	arrays := make(map[string][]float64)
+	for _, f := range zr.File {
+		r, err := f.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, err := io.ReadAll(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, arrays[strings.TrimSuffix(f.Name, ".npy")] = parseNPY(t, data)
+	}
+	return arrays
+}
+
+func TestWriteNPZUnitary(t *testing.T) {
+	for _, pair := range [][2]int{{2, 3}, {3, 2}, {1, 4}, {0, 2}} {
+		table, err := ComputeCGE(pair[0], pair[1])
+		if err != nil {
+			t.Fatal(err)
+		}
+		var buf bytes.Buffer
+		if err := table.WriteNPZ(&buf); err != nil {
+			t.Fatal(err)
+		}
+		arrays := parseNPZ(t, buf.Bytes())
+		if arrays["j1"][0] != table.J1().Float64() || arrays["j2"][0] != table.J2().Float64() {
+			t.Errorf("%v×%v: j1 = %v and j2 = %v", table.J1(), table.J2(), arrays["j1"], arrays["j2"])
+		}
+		n := (pair[0] + 1) * (pair[1] + 1)
+		u, rows, cols := arrays["unitary"], arrays["unitary_rows"], arrays["unitary_cols"]
+		if len(u) != n*n || len(rows) != 2*n || len(cols) != 2*n {
+			t.Fatalf("%v×%v: unitary has %v elements with %v row and %v column labels", table.J1(), table.J2(), len(u), len(rows)/2, len(cols)/2)
+		}
+		// U·Uᵀ = I.
+		for i := 0; i < n; i++ {
+			for k := 0; k < n; k++ {
+				dot := 0.0
+				for l := 0; l < n; l++ {
+					dot += u[i*n+l] * u[k*n+l]
+				}
+				want := 0.0
+				if i == k {
+					want = 1
+				}
+				if math.Abs(dot-want) > 1e-12 {
+					t.Errorf("%v×%v: (U·Uᵀ)[%v][%v] = %v, want %v", table.J1(), table.J2(), i, k, dot, want)
+				}
+			}
+		}
+		// Each element is the coefficient of its labels.
+		for i := 0; i < n; i++ {
+			m1, m2 := NewHalfInteger(int(2*rows[2*i])), NewHalfInteger(int(2*rows[2*i+1]))
+			for k := 0; k < n; k++ {
+				j, m := NewHalfInteger(int(2*cols[2*k])), NewHalfInteger(int(2*cols[2*k+1]))
+				want := 0.0
+				if m1.Add(m2) == m {
+					want = table.QuerySqrt(j.Twice(), m.Twice(), m1.Twice(), m2.Twice()).Float64()
+				}
+				if u[i*n+k] != want {
+					t.Errorf("%v×%v: ⟨%v,%v|%v,%v⟩ = %v in unitary, want %v", table.J1(), table.J2(), m1, m2, j, m, u[i*n+k], want)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package cg
+
+import (
+	"math"
+)
+
+// Options configures ComputeCGContext.
+type Options struct {
+	// Progress, if set, is called each time a cell |j,m⟩ of the table has been computed,
+	// with the number of cells done so far and the total number of cells. Calls are serialized.
+	Progress func(done, total int)
+	// MaxMemory, if positive, is the limit in bytes on the estimated memory of the table.
+	// Computations estimated above it are refused with *MemoryLimitError before any work is done.
+	MaxMemory int64
+	// Pool, if set, runs the column computations, otherwise a process-wide pool with GOMAXPROCS workers is used.
+	// Sharing one pool bounds the total parallelism of several concurrent computations.
+	// Computations on a closed pool fail with ErrPoolClosed.
+	Pool *Pool
+}
+
+// Bytes taken by one stored coefficient apart from its radicand and digit words: the numerator and denominator slots,
+// plus the share of its cell and the slack of the arena.
+const coefficientOverhead = 24
+
+// EstimateMemory estimates the memory in bytes taken by the CG table for the given j1 and j2.
+// Arguments are twice the value of actual j1 and j2 so they are integers.
+func EstimateMemory(twoj1, twoj2 int) int64 {
+	if twoj1 < twoj2 {
+		twoj1, twoj2 = twoj2, twoj1
+	}
+	// The signed squares have numerator and denominator bits together growing roughly like log2((j1+j2+1)!),
+	// the numerator and denominator of the rational part of a typical radical form take about an eighth of that each.
+	lg, _ := math.Lgamma(float64(twoj1+twoj2)/2 + 2)
+	bits := lg / math.Ln2 / 8
+	// The radicand is a bit set over the primes up to j1+j2+j+1, of which there are about n/ln(n).
+	n := float64(twoj1 + twoj2 + 2)
+	radicandWords := int64(math.Ceil(n / math.Log(n) / 64))
+	perCoefficient := coefficientOverhead + 8*radicandWords
+	// Rationals are kept in the slots while they fit in int64, see store. Most no longer do well before the typical size reaches 63 bits.
+	if bits >= 32 {
+		perCoefficient += 8 * 2 * int64(math.Ceil(bits/64))
+	}
+	return coefficientCount(twoj1, twoj2) * perCoefficient
+}
+
+// Reports whether the table for j1 >= j2 stores at most n coefficients, which bounds the memory allocated for
+// tables described by untrusted data. Every state |j,m⟩ with m >= 0 has a coefficient, so j too large for n are
+// refused without counting.
+func storesAtMost(twoj1, twoj2 int, n int64) bool {
+	if twoj1 >= factorLimit || twoj2 >= factorLimit || int64(twoj1+1)*int64(twoj2+1)/2 > n {
+		return false
+	}
+	return coefficientCount(twoj1, twoj2) <= n
+}
+
+// Counts the coefficients stored in the table for j1 >= j2.
+func coefficientCount(twoj1, twoj2 int) int64 {
+	count := int64(0)
+	for dj := 0; dj <= twoj2; dj++ {
+		twoj := twoj1 + twoj2 - 2*dj
+		for i := 0; i < twoj/2+1; i++ {
+			twom := twoj - 2*i
+			// Same m1 range as newColumn.
+			min, max := -twoj1, twoj1
+			if min < twom-twoj2 {
+				min = twom - twoj2
+			}
+			if max > twom+twoj2 {
+				max = twom + twoj2
+			}
+			count += int64((max-min)/2 + 1)
+		}
+	}
+	return count
+}
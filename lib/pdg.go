@@ -0,0 +1,168 @@
+package cg
+
+import (
+	"bufio"
+	"fmt"
+	"html"
+	"io"
+	"strings"
+)
+
+// The compact layout of CG tables used by the Particle Data Group: a small block per m, each with the j and m of its
+// columns as headings and m1, m2 as labels of its rows, placed on a diagonal so the headings of a block sit to the right
+// of the last rows of the one before. Coefficients are written as their signed squares, the square root is implied.
+type pdgLayout struct {
+	// Size of the grid of the cells.
+	rows   int
+	cols   int
+	title  string
+	cells  []pdgCell
+	blocks []pdgBlock
+}
+
+type pdgCellKind int
+
+const (
+	pdgJ pdgCellKind = iota
+	pdgM
+	pdgM1
+	pdgM2
+	pdgCoefficient
+)
+
+type pdgCell struct {
+	row  int
+	col  int
+	kind pdgCellKind
+	text string
+}
+
+// Position of a block: the first row of its two heading rows, the first of its two label columns, and its size without them.
+type pdgBlock struct {
+	top  int
+	left int
+	rows int
+	cols int
+}
+
+func (t *Table) pdgLayout() *pdgLayout {
+	l := &pdgLayout{title: fmt.Sprintf("%v×%v", t.J1(), t.J2())}
+	top, left := 0, 0
+	twojmax := t.twoj1 + t.twoj2
+	// Blocks are in order of decreasing m, their rows of decreasing m1 whichever of j1 and j2 is larger.
+	for twom := twojmax; twom >= -twojmax; twom -= 2 {
+		blk := t.Block(NewHalfInteger(twom))
+		b := pdgBlock{top: top, left: left, rows: len(blk.M1), cols: len(blk.J)}
+		for dj, j := range blk.J {
+			col := b.left + 2 + dj
+			l.add(b.top, col, pdgJ, j.String())
+			l.add(b.top+1, col, pdgM, pdgSigned(blk.M))
+		}
+		for i, row := range blk.C {
+			r := b.top + 2 + i
+			l.add(r, b.left, pdgM1, pdgSigned(blk.M1[i]))
+			l.add(r, b.left+1, pdgM2, pdgSigned(blk.M2[i]))
+			for dj, c := range row {
+				l.add(r, b.left+2+dj, pdgCoefficient, strings.Replace(c.SignedSquare().RatString(), "-", "−", 1))
+			}
+		}
+		l.blocks = append(l.blocks, b)
+		l.rows, l.cols = b.top+2+b.rows, b.left+2+b.cols
+		// The headings of the next block take the last two rows of this one, its labels the last two columns.
+		top, left = b.top+b.rows, b.left+b.cols
+	}
+	return l
+}
+
+func (l *pdgLayout) add(row, col int, kind pdgCellKind, text string) {
+	l.cells = append(l.cells, pdgCell{row: row, col: col, kind: kind, text: text})
+}
+
+// Formats m with its sign like the PDG tables, e.g., "+3/2", "0", "−1/2".
+func pdgSigned(h HalfInteger) string {
+	switch {
+	case h.Twice() > 0:
+		return "+" + h.String()
+	case h.Twice() < 0:
+		return "−" + h.Abs().String()
+	}
+	return "0"
+}
+
+// Classes of the kinds of cells in HTML.
+var pdgClasses = map[pdgCellKind]string{
+	pdgJ:           "heading",
+	pdgM:           "heading m",
+	pdgM1:          "label",
+	pdgM2:          "label m2",
+	pdgCoefficient: "coefficient",
+}
+
+// Explains the layout above the tables.
+var pdgNote = []string{
+	"Each block is headed by j and m, its rows are labelled by m1 and m2.",
+	"A square root is implied on every coefficient, keeping the sign: −1/2 means −√(1/2).",
+}
+
+const pdgStyle = `html,body {
+  margin: 0;
+  padding: 10px;
+  font-family: sans-serif;
+}
+.tables {
+  display: flex;
+  flex-wrap: wrap;
+  align-items: flex-start;
+  gap: 32px;
+}
+.pdg {
+  display: grid;
+  font-size: 13px;
+}
+.pdg div {
+  padding: 2px 6px;
+  text-align: center;
+  white-space: nowrap;
+}
+.pdg .title {
+  font-weight: bold;
+  text-align: left;
+}
+.pdg .heading {
+  background-color: #dfe7f2;
+}
+.pdg .heading.m {
+  border-bottom: 1px solid #000;
+}
+.pdg .label.m2 {
+  border-right: 1px solid #000;
+}
+.pdg .coefficient {
+  background-color: #f2f2f2;
+}
+`
+
+// WritePDGHTML renders the tables in the compact layout of the Particle Data Group as an HTML page to w, side by side
+// in the order given, wrapping to the width of the page. Like the PDG tables, each m is a small block with the square
+// root of its coefficients implied.
+// Failures are reported as *WriteError.
+func WritePDGHTML(w io.Writer, tables ...*Table) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "<!DOCTYPE html>\n<html>\n<head>\n<meta charset=\"utf-8\">\n<style>\n%v</style>\n</head>\n<body>\n", pdgStyle)
+	fmt.Fprintf(bw, "<p>%v</p>\n<div class=\"tables\">\n", html.EscapeString(strings.Join(pdgNote, " ")))
+	for _, t := range tables {
+		l := t.pdgLayout()
+		fmt.Fprintf(bw, "<div class=\"pdg\" style=\"grid-template-columns: repeat(%v, auto)\">\n", l.cols)
+		fmt.Fprintf(bw, "  <div class=\"title\" style=\"grid-area: 1 / 1 / 3 / 3\">%v</div>\n", html.EscapeString(l.title))
+		for _, c := range l.cells {
+			class := pdgClasses[c.kind]
+			fmt.Fprintf(bw, "  <div class=\"%v\" style=\"grid-area: %v / %v\">%v</div>\n", class, c.row+1, c.col+1, html.EscapeString(c.text))
+		}
+		fmt.Fprint(bw, "</div>\n")
+	}
+	fmt.Fprint(bw, "</div>\n</body>\n</html>\n")
+	if err := bw.Flush(); err != nil {
+		return &WriteError{Err: err}
+	}
+	return nil
+}
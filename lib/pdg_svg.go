@@ -0,0 +1,125 @@
+package cg
+
+import (
+	"bufio"
+	"fmt"
+	"html"
+	"io"
+	"math"
+	"unicode/utf8"
+)
+
+// Metrics of the SVG form of the PDG layout, in pixels. Text is monospace so its width can be told from its length.
+const (
+	pdgFontSize  = 13
+	pdgCharWidth = 0.6 * pdgFontSize
+	pdgPadding   = 6
+	pdgRowHeight = 20
+	// Space between tables, and the least width of a page before tables wrap to the next line.
+	pdgGap       = 32
+	pdgPageWidth = 960
+)
+
+// Size of a table in the SVG form and the left edges of its columns, followed by the right edge of the last one.
+type pdgSVGTable struct {
+	l      *pdgLayout
+	x      []float64
+	width  float64
+	height float64
+}
+
+func newPDGSVGTable(l *pdgLayout) *pdgSVGTable {
+	widths := make([]float64, l.cols)
+	for _, c := range l.cells {
+		if w := pdgTextWidth(c.text); w > widths[c.col] {
+			widths[c.col] = w
+		}
+	}
+	// The title spans the first two columns.
+	if w := pdgTextWidth(l.title); w > widths[0]+widths[1] {
+		widths[1] = w - widths[0]
+	}
+	st := &pdgSVGTable{l: l, x: make([]float64, l.cols+1), height: float64(l.rows * pdgRowHeight)}
+	for i, w := range widths {
+		st.x[i+1] = st.x[i] + w
+	}
+	st.width = st.x[l.cols]
+	return st
+}
+
+// Rounds up to whole pixels, keeping the coordinates short.
+func pdgTextWidth(text string) float64 {
+	return math.Ceil(float64(utf8.RuneCountInString(text))*pdgCharWidth + 2*pdgPadding)
+}
+
+func (st *pdgSVGTable) y(row int) float64 {
+	return float64(row * pdgRowHeight)
+}
+
+// Writes the table at the given offset.
+func (st *pdgSVGTable) write(bw *bufio.Writer, dx, dy float64) {
+	fmt.Fprintf(bw, "<g transform=\"translate(%v,%v)\">\n", dx, dy)
+	for _, b := range st.l.blocks {
+		left, mid, right := st.x[b.left], st.x[b.left+2], st.x[b.left+2+b.cols]
+		top, mid2, bottom := st.y(b.top), st.y(b.top+2), st.y(b.top+2+b.rows)
+		fmt.Fprintf(bw, "<rect x=\"%v\" y=\"%v\" width=\"%v\" height=\"%v\" fill=\"#dfe7f2\"/>\n", mid, top, right-mid, mid2-top)
+		fmt.Fprintf(bw, "<rect x=\"%v\" y=\"%v\" width=\"%v\" height=\"%v\" fill=\"#f2f2f2\"/>\n", mid, mid2, right-mid, bottom-mid2)
+		fmt.Fprintf(bw, "<line x1=\"%v\" y1=\"%v\" x2=\"%v\" y2=\"%v\" stroke=\"#000\"/>\n", left, mid2, right, mid2)
+		fmt.Fprintf(bw, "<line x1=\"%v\" y1=\"%v\" x2=\"%v\" y2=\"%v\" stroke=\"#000\"/>\n", mid, top, mid, bottom)
+	}
+	fmt.Fprintf(bw, "<text x=\"%v\" y=\"%v\" font-weight=\"bold\">%v</text>\n", pdgPadding, st.baseline(0), html.EscapeString(st.l.title))
+	for _, c := range st.l.cells {
+		x := (st.x[c.col] + st.x[c.col+1]) / 2
+		fmt.Fprintf(bw, "<text x=\"%v\" y=\"%v\" text-anchor=\"middle\">%v</text>\n", x, st.baseline(c.row), html.EscapeString(c.text))
+	}
+	fmt.Fprint(bw, "</g>\n")
+}
+
+func (st *pdgSVGTable) baseline(row int) float64 {
+	return st.y(row) + 0.7*pdgRowHeight
+}
+
+// WritePDGSVG renders the tables in the compact layout of the Particle Data Group as an SVG image to w, like WritePDGHTML,
+// side by side in the order given and wrapping to the next line beyond a width of 960 pixels or the widest table.
+// Failures are reported as *WriteError.
+func WritePDGSVG(w io.Writer, tables ...*Table) error {
+	sts := make([]*pdgSVGTable, len(tables))
+	pageWidth := float64(pdgPageWidth)
+	for i, t := range tables {
+		sts[i] = newPDGSVGTable(t.pdgLayout())
+		if sts[i].width > pageWidth {
+			pageWidth = sts[i].width
+		}
+	}
+	// Place the tables in lines below the note, each line as high as its highest table.
+	type placement struct{ x, y float64 }
+	places := make([]placement, len(sts))
+	x, y, lineHeight := 0.0, float64((len(pdgNote)+1)*pdgRowHeight), 0.0
+	for i, st := range sts {
+		if x > 0 && x+st.width > pageWidth {
+			x, y, lineHeight = 0, y+lineHeight+pdgGap, 0
+		}
+		places[i] = placement{x, y}
+		x += st.width + pdgGap
+		if st.height > lineHeight {
+			lineHeight = st.height
+		}
+	}
+	height := y + lineHeight
+
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"%v\" height=\"%v\" viewBox=\"0 0 %v %v\" font-family=\"monospace\" font-size=\"%v\">\n",
+		pageWidth, height, pageWidth, height, pdgFontSize)
+	fmt.Fprint(bw, "<rect width=\"100%\" height=\"100%\" fill=\"#fff\"/>\n")
+	for i, line := range pdgNote {
+		fmt.Fprintf(bw, "<text x=\"0\" y=\"%v\">%v</text>\n", float64(i*pdgRowHeight)+0.7*pdgRowHeight, html.EscapeString(line))
+	}
+	for i, st := range sts {
+		st.write(bw, places[i].x, places[i].y)
+	}
+	fmt.Fprint(bw, "</svg>\n")
+	if err := bw.Flush(); err != nil {
+		return &WriteError{Err: err}
+	}
+	return nil
+}
@@ -0,0 +1,200 @@
+package cg
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"html"
+	"io"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// Returns the cells of the layout by their position, failing on cells outside the grid or sharing a position.
+func pdgGrid(t *testing.T, l *pdgLayout) map[[2]int]pdgCell {
+	t.Helper()
+	grid := make(map[[2]int]pdgCell)
+	for _, c := range l.cells {
+		if c.row < 0 || c.row >= l.rows || c.col < 0 || c.col >= l.cols {
+			t.Errorf("%v: cell %q at (%v, %v) outside the grid of %v×%v", l.title, c.text, c.row, c.col, l.rows, l.cols)
+		}
+		pos := [2]int{c.row, c.col}
+		if prev, ok := grid[pos]; ok {
+			t.Errorf("%v: cells %q and %q both at (%v, %v)", l.title, prev.text, c.text, c.row, c.col)
+		}
+		grid[pos] = c
+	}
+	return grid
+}
+
+// Returns the text of the cell of the kind at the position of the grid.
+func pdgText(t *testing.T, l *pdgLayout, grid map[[2]int]pdgCell, row, col int, kind pdgCellKind) string {
+	t.Helper()
+	c, ok := grid[[2]int{row, col}]
+	if !ok || c.kind != kind {
+		t.Fatalf("%v: no cell of kind %v at (%v, %v), found %+v", l.title, kind, row, col, c)
+	}
+	return c.text
+}
+
+func pdgHalfInteger(t *testing.T, text string) HalfInteger {
+	t.Helper()
+	h, err := ParseHalfIntegerValue(text)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return h
+}
+
+// Checks the layouts of 1×1/2 and 1/2×1: the same blocks and headings, rows of decreasing m1 in both, and each
+// coefficient the signed square of its labels.
+func TestPDGLayoutExchanged(t *testing.T) {
+	var layouts []*pdgLayout
+	for _, pair := range [][2]int{{2, 1}, {1, 2}} {
+		table, err := ComputeCGE(pair[0], pair[1])
+		if err != nil {
+			t.Fatal(err)
+		}
+		l := table.pdgLayout()
+		layouts = append(layouts, l)
+		grid := pdgGrid(t, l)
+		if len(grid) != len(l.cells) {
+			continue
+		}
+		if len(l.blocks) != 4 {
+			t.Fatalf("%v has %v blocks, want one for each m of 3/2 down to -3/2", l.title, len(l.blocks))
+		}
+		for _, b := range l.blocks {
+			m := pdgHalfInteger(t, pdgText(t, l, grid, b.top+1, b.left+2, pdgM))
+			for i := 0; i < b.rows; i++ {
+				r := b.top + 2 + i
+				m1 := pdgHalfInteger(t, pdgText(t, l, grid, r, b.left, pdgM1))
+				m2 := pdgHalfInteger(t, pdgText(t, l, grid, r, b.left+1, pdgM2))
+				if m1.Add(m2) != m {
+					t.Errorf("%v: row %v of the block of m = %v is labelled m1 = %v, m2 = %v", l.title, i, m, m1, m2)
+				}
+				if i > 0 {
+					if prev := pdgHalfInteger(t, pdgText(t, l, grid, r-1, b.left, pdgM1)); m1.Twice() >= prev.Twice() {
+						t.Errorf("%v: in the block of m = %v, m1 = %v follows m1 = %v", l.title, m, m1, prev)
+					}
+				}
+				for dj := 0; dj < b.cols; dj++ {
+					j := pdgHalfInteger(t, pdgText(t, l, grid, b.top, b.left+2+dj, pdgJ))
+					want := strings.Replace(table.Query(j.Twice(), m.Twice(), m1.Twice(), m2.Twice()).RatString(), "-", "−", 1)
+					if got := pdgText(t, l, grid, r, b.left+2+dj, pdgCoefficient); got != want {
+						t.Errorf("%v: ⟨%v,%v|%v,%v⟩ laid out as %v, want %v", l.title, m1, m2, j, m, got, want)
+					}
+				}
+			}
+		}
+	}
+	// Exchanging j1 and j2 keeps the shape of the layout and its headings.
+	a, b := layouts[0], layouts[1]
+	if a.rows != b.rows || a.cols != b.cols || fmt.Sprint(a.blocks) != fmt.Sprint(b.blocks) {
+		t.Fatalf("%v and %v have different blocks: %v in %v×%v and %v in %v×%v", a.title, b.title, a.blocks, a.rows, a.cols, b.blocks, b.rows, b.cols)
+	}
+	ga, gb := pdgGrid(t, a), pdgGrid(t, b)
+	for pos, c := range ga {
+		if c.kind == pdgJ || c.kind == pdgM {
+			if d := gb[pos]; d != c {
+				t.Errorf("heading %q of %v at %v is %q in %v", c.text, a.title, pos, d.text, b.title)
+			}
+		}
+	}
+}
+
+var pdgHTMLCellPattern = regexp.MustCompile(`<div class="([a-z0-9 ]+)" style="grid-area: (\d+) / (\d+)">([^<]*)</div>`)
+
+// Lays out two pairs on one page with the registered multi-table renderers.
+func TestRenderTablesPDG(t *testing.T) {
+	var tables []*Table
+	for _, pair := range [][2]int{{1, 1}, {1, 2}} {
+		table, err := ComputeCGE(pair[0], pair[1])
+		if err != nil {
+			t.Fatal(err)
+		}
+		tables = append(tables, table)
+	}
+	render := func(name string) string {
+		t.Helper()
+		r, _, ok := LookupRenderer(name)
+		if !ok {
+			t.Fatalf("no renderer %v", name)
+		}
+		mr, ok := r.(MultiRenderer)
+		if !ok {
+			t.Fatalf("renderer %v is not a MultiRenderer", name)
+		}
+		var buf bytes.Buffer
+		if err := mr.RenderTables(&buf, tables...); err != nil {
+			t.Fatal(err)
+		}
+		return buf.String()
+	}
+
+	// Every table of the HTML page holds the cells of its layout, in the order given.
+	pages := strings.Split(render("pdg-html"), `<div class="pdg" `)
+	if len(pages) != len(tables)+1 {
+		t.Fatalf("pdg-html laid out %v tables, want %v", len(pages)-1, len(tables))
+	}
+	for i, table := range tables {
+		l := table.pdgLayout()
+		if !strings.Contains(pages[i+1], ">"+html.EscapeString(l.title)+"<") {
+			t.Errorf("table %v of the pdg-html page is not titled %v", i, l.title)
+		}
+		var got []string
+		for _, m := range pdgHTMLCellPattern.FindAllStringSubmatch(pages[i+1], -1) {
+			got = append(got, fmt.Sprintf("%v %v/%v %v", m[1], m[2], m[3], html.UnescapeString(m[4])))
+		}
+		var want []string
+		for _, c := range l.cells {
+			want = append(want, fmt.Sprintf("%v %v/%v %v", pdgClasses[c.kind], c.row+1, c.col+1, c.text))
+		}
+		if strings.Join(got, "\n") != strings.Join(want, "\n") {
+			t.Errorf("table %v of the pdg-html page has the cells\n%v\nwant\n%v", l.title, strings.Join(got, "\n"), strings.Join(want, "\n"))
+		}
+	}
+
+	// The SVG image is well-formed, with a group for each table holding its title and cells in order.
+	d := xml.NewDecoder(strings.NewReader(render("pdg-svg")))
+	var groups [][]string
+	inText := false
+	for {
+		tok, err := d.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("pdg-svg: %v", err)
+		}
+		switch tok := tok.(type) {
+		case xml.StartElement:
+			switch tok.Name.Local {
+			case "g":
+				groups = append(groups, nil)
+			case "text":
+				inText = len(groups) > 0
+			}
+		case xml.EndElement:
+			inText = false
+		case xml.CharData:
+			if inText {
+				groups[len(groups)-1] = append(groups[len(groups)-1], string(tok))
+			}
+		}
+	}
+	if len(groups) != len(tables) {
+		t.Fatalf("pdg-svg laid out %v tables, want %v", len(groups), len(tables))
+	}
+	for i, table := range tables {
+		l := table.pdgLayout()
+		want := []string{l.title}
+		for _, c := range l.cells {
+			want = append(want, c.text)
+		}
+		if strings.Join(groups[i], " ") != strings.Join(want, " ") {
+			t.Errorf("table %v of the pdg-svg image has the texts %v, want %v", l.title, groups[i], want)
+		}
+	}
+}
@@ -0,0 +1,137 @@
+package cg
+
+import (
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// Pool is a bounded set of worker goroutines computing table columns.
+// One pool may be shared by several computations running at the same time, see Options.Pool.
+type Pool struct {
+	workers int
+
+	mu     sync.Mutex
+	cond   *sync.Cond
+	tasks  []func()
+	closed bool
+	wg     sync.WaitGroup
+}
+
+var (
+	defaultPoolOnce sync.Once
+	defaultPoolInst *Pool
+)
+
+// NewPool starts a pool with the given number of workers, or GOMAXPROCS workers if workers <= 0.
+func NewPool(workers int) *Pool {
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	p := &Pool{workers: workers}
+	p.cond = sync.NewCond(&p.mu)
+	p.wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go p.work()
+	}
+	return p
+}
+
+// Returns the process-wide pool used when Options.Pool is not set.
+func defaultPool() *Pool {
+	defaultPoolOnce.Do(func() {
+		defaultPoolInst = NewPool(0)
+	})
+	return defaultPoolInst
+}
+
+// Close stops the workers once all queued tasks are done and waits for them to exit.
+// Computations started on a closed pool, or still queuing columns when it is closed, fail with ErrPoolClosed.
+func (p *Pool) Close() {
+	p.mu.Lock()
+	p.closed = true
+	p.cond.Broadcast()
+	p.mu.Unlock()
+	p.wg.Wait()
+}
+
+// Queues a task, never blocks so tasks may submit further tasks. Returns ErrPoolClosed if the pool is closed.
+func (p *Pool) submit(task func()) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.closed {
+		return ErrPoolClosed
+	}
+	p.tasks = append(p.tasks, task)
+	p.cond.Signal()
+	return nil
+}
+
+func (p *Pool) work() {
+	defer p.wg.Done()
+	for {
+		p.mu.Lock()
+		for len(p.tasks) == 0 && !p.closed {
+			p.cond.Wait()
+		}
+		if len(p.tasks) == 0 {
+			p.mu.Unlock()
+			return
+		}
+		task := p.tasks[0]
+		p.tasks[0] = nil
+		p.tasks = p.tasks[1:]
+		p.mu.Unlock()
+		task()
+	}
+}
+
+// Runs fn over consecutive chunks of [0,n) of the given grain size and returns the first error.
+// Chunks are claimed by the calling task and by helpers queued for idle workers, so the caller never waits on a chunk that has not started.
+// A nil pool runs everything in the calling goroutine.
+func (p *Pool) parallelFor(n, grain int, fn func(lo, hi int) error) error {
+	chunks := (n + grain - 1) / grain
+	if p == nil || p.workers <= 1 || chunks <= 1 {
+		return fn(0, n)
+	}
+	var (
+		next int32
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		err  error
+	)
+	runChunks := func() {
+		for {
+			k := int(atomic.AddInt32(&next, 1)) - 1
+			if k >= chunks {
+				return
+			}
+			hi := (k + 1) * grain
+			if hi > n {
+				hi = n
+			}
+			if e := fn(k*grain, hi); e != nil {
+				mu.Lock()
+				if err == nil {
+					err = e
+				}
+				mu.Unlock()
+			}
+			wg.Done()
+		}
+	}
+	wg.Add(chunks)
+	helpers := chunks - 1
+	if helpers > p.workers-1 {
+		helpers = p.workers - 1
+	}
+	for h := 0; h < helpers; h++ {
+		// Without helpers the caller claims all chunks itself.
+		if p.submit(runChunks) != nil {
+			break
+		}
+	}
+	runChunks()
+	wg.Wait()
+	return err
+}
@@ -0,0 +1,40 @@
+package cg
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestComputeOnClosedPool(t *testing.T) {
+	pool := NewPool(2)
+	pool.Close()
+	if _, err := ComputeCGContext(context.Background(), 6, 4, &Options{Pool: pool}); !errors.Is(err, ErrPoolClosed) {
+		t.Errorf("ComputeCGContext on a closed pool returned %v, want ErrPoolClosed", err)
+	}
+	if _, err := ComputeAll(4, &Options{Pool: pool}); !errors.Is(err, ErrPoolClosed) {
+		t.Errorf("ComputeAll on a closed pool returned %v, want ErrPoolClosed", err)
+	}
+}
+
+// Compares rung-level parallelism at several grain sizes, see lowerGrain, with column-only parallelism ("off").
+func BenchmarkComputeCG(b *testing.B) {
+	defer func(grain int) { lowerGrain = grain }(lowerGrain)
+	grains := []struct {
+		name  string
+		grain int
+	}{{"off", 1 << 30}, {"8", 8}, {"32", 32}, {"128", 128}}
+	for _, twoj := range []int{60, 120, 200} {
+		for _, g := range grains {
+			b.Run(fmt.Sprintf("j=%v/grain=%v", FormatHalfInteger(twoj), g.name), func(b *testing.B) {
+				lowerGrain = g.grain
+				for i := 0; i < b.N; i++ {
+					if _, err := ComputeCGE(twoj, twoj); err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+		}
+	}
+}
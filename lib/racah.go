@@ -0,0 +1,78 @@
+package cg
+
+import (
+	"math/big"
+)
+
+// Coefficient evaluates the single CG coefficient ⟨j1,m1;j2,m2|j,m⟩ with the closed-form Racah formula,
+// without building the whole table. All arguments are twice the actual values so they are integers.
+// The returned value is the signed square of the coefficient, identical to what Table.Query returns;
+// it is zero for any combination forbidden by the selection rules.
+func Coefficient(twoj1, twom1, twoj2, twom2, twoj, twom int) *big.Rat {
+	j1, j2, j := NewHalfInteger(twoj1), NewHalfInteger(twoj2), NewHalfInteger(twoj)
+	if twom != twom1+twom2 ||
+		!IsProjection(j1, NewHalfInteger(twom1)) ||
+		!IsProjection(j2, NewHalfInteger(twom2)) ||
+		!IsProjection(j, NewHalfInteger(twom)) ||
+		!Triangle(j1, j2, j) {
+		return BlankRat()
+	}
+	// All of the following are whole numbers once the selection rules hold.
+	j1pj2mj := (twoj1 + twoj2 - twoj) / 2
+	j1mj2pj := (twoj1 - twoj2 + twoj) / 2
+	j2mj1pj := (twoj2 - twoj1 + twoj) / 2
+	j1mm1, j1pm1 := (twoj1-twom1)/2, (twoj1+twom1)/2
+	j2mm2, j2pm2 := (twoj2-twom2)/2, (twoj2+twom2)/2
+	jmm, jpm := (twoj-twom)/2, (twoj+twom)/2
+	jmj2pm1 := (twoj - twoj2 + twom1) / 2
+	jmj1mm2 := (twoj - twoj1 - twom2) / 2
+
+	// Square of the prefactor:
+	// (2j+1)(j1+j2-j)!(j1-j2+j)!(-j1+j2+j)!/(j1+j2+j+1)! × (j+m)!(j-m)!(j1-m1)!(j1+m1)!(j2-m2)!(j2+m2)!
+	num := big.NewInt(int64(twoj + 1))
+	for _, n := range []int{j1pj2mj, j1mj2pj, j2mj1pj, jpm, jmm, j1mm1, j1pm1, j2mm2, j2pm2} {
+		num.Mul(num, factorial(n))
+	}
+	prefactor := BlankRat().SetFrac(num, factorial((twoj1+twoj2+twoj)/2+1))
+
+	// Σ_k (-1)^k/(k!(j1+j2-j-k)!(j1-m1-k)!(j2+m2-k)!(j-j2+m1+k)!(j-j1-m2+k)!), over all k keeping the factorial arguments non-negative.
+	kmin := 0
+	if kmin < -jmj2pm1 {
+		kmin = -jmj2pm1
+	}
+	if kmin < -jmj1mm2 {
+		kmin = -jmj1mm2
+	}
+	kmax := j1pj2mj
+	if kmax > j1mm1 {
+		kmax = j1mm1
+	}
+	if kmax > j2pm2 {
+		kmax = j2pm2
+	}
+	sum := BlankRat()
+	term := BlankRat()
+	for k := kmin; k <= kmax; k++ {
+		denom := big.NewInt(1)
+		for _, n := range []int{k, j1pj2mj - k, j1mm1 - k, j2pm2 - k, jmj2pm1 + k, jmj1mm2 + k} {
+			denom.Mul(denom, factorial(n))
+		}
+		term.SetFrac(big.NewInt(1), denom)
+		if k%2 != 0 {
+			term.Neg(term)
+		}
+		sum.Add(sum, term)
+	}
+
+	// Signed square of √prefactor×sum.
+	ret := BlankRat().Mul(sum, sum)
+	ret.Mul(ret, prefactor)
+	if sum.Sign() < 0 {
+		ret.Neg(ret)
+	}
+	return ret
+}
+
+func factorial(n int) *big.Int {
+	return BlankInt().MulRange(1, int64(n))
+}
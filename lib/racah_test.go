@@ -0,0 +1,33 @@
+package cg
+
+import "testing"
+
+// Compares the Racah formula with the tables computed by the ladder algorithm, including the zeros where m2 ≠ m-m1
+// is out of range.
+func TestCoefficientMatchesLadder(t *testing.T) {
+	const maxTwoj = 12
+	for twoj1 := 0; twoj1 <= maxTwoj; twoj1++ {
+		for twoj2 := 0; twoj2 <= maxTwoj; twoj2++ {
+			table, err := ComputeCGE(twoj1, twoj2)
+			if err != nil {
+				t.Fatalf("ComputeCGE(%v, %v): %v", twoj1, twoj2, err)
+			}
+			twojmin := twoj1 - twoj2
+			if twojmin < 0 {
+				twojmin = -twojmin
+			}
+			for twoj := twojmin; twoj <= twoj1+twoj2; twoj += 2 {
+				for twom := -twoj; twom <= twoj; twom += 2 {
+					for twom1 := -twoj1; twom1 <= twoj1; twom1 += 2 {
+						twom2 := twom - twom1
+						want := table.Query(twoj, twom, twom1, twom2)
+						if got := Coefficient(twoj1, twom1, twoj2, twom2, twoj, twom); got.Cmp(want) != 0 {
+							t.Errorf("Coefficient(%v, %v, %v, %v, %v, %v) = %v, the table has %v",
+								twoj1, twom1, twoj2, twom2, twoj, twom, got.RatString(), want.RatString())
+						}
+					}
+				}
+			}
+		}
+	}
+}
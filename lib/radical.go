@@ -0,0 +1,278 @@
+package cg
+
+import (
+	"math/big"
+	"math/bits"
+	"sync"
+)
+
+// Represents the exact value r√s of a coefficient, where r is a signed rational and s a square-free integer.
+// CG coefficients are products of factorial ratios, so s only has small prime factors and is stored as the set of them.
+// Two radicals with the same s add by adding r, no integer square roots are needed.
+// The zero value is 0.
+type radical struct {
+	r rat
+	s primeSet
+}
+
+// A square-free integer as the set of its prime factors, bit k stands for the k-th prime, see getSmallPrimes.
+type primeSet []uint64
+
+// Largest integer handled by the factor tables, all prime factors of coefficients with j1+j2 below it are covered.
+const factorLimit = 1 << 16
+
+var (
+	factorTablesOnce sync.Once
+	// Smallest prime factor of each integer below factorLimit.
+	smallestPrimeFactor []uint16
+	// Index of each prime below factorLimit in getSmallPrimes.
+	primeIndex []uint16
+)
+
+func getFactorTables() ([]uint16, []uint16) {
+	factorTablesOnce.Do(func() {
+		smallestPrimeFactor = make([]uint16, factorLimit)
+		primeIndex = make([]uint16, factorLimit)
+		for k, p := range getSmallPrimes() {
+			primeIndex[p] = uint16(k)
+			for q := p; q < factorLimit; q += p {
+				if smallestPrimeFactor[q] == 0 {
+					smallestPrimeFactor[q] = uint16(p)
+				}
+			}
+		}
+	})
+	return smallestPrimeFactor, primeIndex
+}
+
+func (s primeSet) has(k int) bool {
+	return k/64 < len(s) && s[k/64]&(1<<(k%64)) != 0
+}
+
+// Flips prime k in place, s must be large enough.
+func (s primeSet) flip(k int) {
+	s[k/64] ^= 1 << (k % 64)
+}
+
+// Returns a copy of s with room for prime k.
+func (s primeSet) grow(k int) primeSet {
+	n := len(s)
+	if k/64+1 > n {
+		n = k/64 + 1
+	}
+	ret := make(primeSet, n)
+	copy(ret, s)
+	return ret
+}
+
+func (s primeSet) equal(o primeSet) bool {
+	if len(s) < len(o) {
+		s, o = o, s
+	}
+	for i := range s {
+		w := uint64(0)
+		if i < len(o) {
+			w = o[i]
+		}
+		if s[i] != w {
+			return false
+		}
+	}
+	return true
+}
+
+// Returns the integer represented by s.
+func (s primeSet) product() *big.Int {
+	primes := getSmallPrimes()
+	ret := big.NewInt(1)
+	for i, w := range s {
+		for ; w != 0; w &= w - 1 {
+			ret.Mul(ret, big.NewInt(primes[i*64+bits.TrailingZeros64(w)]))
+		}
+	}
+	return ret
+}
+
+// Multiplies the square-free integers s and o, returning the new square-free part and the product of the common primes squared out.
+func (s primeSet) mul(o primeSet) (primeSet, *big.Int) {
+	if len(s) < len(o) {
+		s, o = o, s
+	}
+	ret := make(primeSet, len(s))
+	copy(ret, s)
+	common := primeSet(nil)
+	for i, w := range o {
+		ret[i] ^= w
+		if s[i]&w != 0 {
+			if common == nil {
+				common = make(primeSet, len(o))
+			}
+			common[i] = s[i] & w
+		}
+	}
+	if common == nil {
+		return ret, big.NewInt(1)
+	}
+	return ret, common.product()
+}
+
+func (x radical) isZero() bool {
+	return x.r.sign() == 0
+}
+
+// Returns the signed square sign(r)r²s, the representation returned by Table.Query.
+func (x radical) signedSquare() *big.Rat {
+	if x.isZero() {
+		return BlankRat()
+	}
+	r := x.r.big()
+	ret := BlankRat().Mul(r, r)
+	ret.Mul(ret, BlankRat().SetInt(x.s.product()))
+	if x.r.sign() < 0 {
+		ret.Neg(ret)
+	}
+	return ret
+}
+
+// Returns the square r²s.
+func (x radical) square() *big.Rat {
+	return BlankRat().Abs(x.signedSquare())
+}
+
+func (x radical) neg() radical {
+	if x.isZero() {
+		return radical{}
+	}
+	return radical{r: x.r.neg(), s: x.s}
+}
+
+func (x radical) mul(o radical) radical {
+	if x.isZero() || o.isZero() {
+		return radical{}
+	}
+	s, common := x.s.mul(o.s)
+	r := x.r.mul(o.r)
+	if common.IsInt64() {
+		r = r.mulInt(common.Int64())
+	} else {
+		r = r.mul(ratFromBig(BlankRat().SetInt(common)))
+	}
+	return radical{r: r, s: s}
+}
+
+// Returns x/o, o must not be zero.
+func (x radical) quo(o radical) radical {
+	if x.isZero() {
+		return radical{}
+	}
+	// 1/(r√s)=√s/(rs).
+	inv := o.r.mul(ratFromBig(BlankRat().SetInt(o.s.product()))).inv()
+	return x.mul(radical{r: inv, s: o.s})
+}
+
+// Returns x√(f1×f2×...) for small non-negative integer factors.
+func (x radical) mulSqrt(factors ...int) radical {
+	if x.isZero() {
+		return radical{}
+	}
+	spf, index := getFactorTables()
+	s := x.s
+	// s is shared with x until the first copy.
+	owned := false
+	squared := int64(1)
+	for _, f := range factors {
+		if f == 0 {
+			return radical{}
+		}
+		for f > 1 {
+			p := int(spf[f])
+			f /= p
+			k := int(index[p])
+			if !owned || k/64 >= len(s) {
+				s = s.grow(k)
+				owned = true
+			}
+			if s.has(k) {
+				squared *= int64(p)
+			}
+			s.flip(k)
+		}
+	}
+	return radical{r: x.r.mulInt(squared), s: s}
+}
+
+// Returns x/√(f1×f2×...) for small positive integer factors.
+func (x radical) quoSqrt(factors ...int) radical {
+	ret := x.mulSqrt(factors...)
+	if ret.isZero() {
+		return ret
+	}
+	denom := int64(1)
+	for _, f := range factors {
+		denom *= int64(f)
+	}
+	ret.r = ret.r.quoInt(denom)
+	return ret
+}
+
+// Returns the radical whose signed square is sq, all prime factors of the square-free part of sq must be below maxPrime.
+func radicalFromSignedSquare(sq *big.Rat, maxPrime int) (radical, error) {
+	if sq.Sign() == 0 {
+		return radical{}, nil
+	}
+	// √(p/q)=√(pq)/q.
+	n := BlankInt().Mul(sq.Num(), sq.Denom())
+	n.Abs(n)
+	primes := getSmallPrimes()
+	var s primeSet
+	a := big.NewInt(1)
+	p, q, r := BlankInt(), BlankInt(), BlankInt()
+	for k := 0; k < len(primes) && primes[k] <= int64(maxPrime) && !isOne(n); k++ {
+		p.SetInt64(primes[k])
+		odd := false
+		for {
+			q.QuoRem(n, p, r)
+			if r.Sign() != 0 {
+				break
+			}
+			n.Set(q)
+			if odd {
+				a.Mul(a, p)
+			}
+			odd = !odd
+		}
+		if odd {
+			s = s.grow(k)
+			s.flip(k)
+		}
+	}
+	if !isOne(n) {
+		// Larger primes can only appear squared.
+		root := BlankInt().Sqrt(n)
+		if BlankInt().Mul(root, root).Cmp(n) != 0 {
+			return radical{}, &RadicandError{Square: BlankRat().Set(sq), MaxPrime: maxPrime}
+		}
+		a.Mul(a, root)
+	}
+	ret := BlankRat().SetFrac(a, sq.Denom())
+	if sq.Sign() < 0 {
+		ret.Neg(ret)
+	}
+	return radical{r: ratFromBig(ret), s: s}, nil
+}
+
+// Accumulates v onto sum, which only works for the same square-free part s.
+func accum(sum *radical, v radical) error {
+	if v.isZero() {
+		return nil
+	}
+	if sum.isZero() {
+		*sum = v
+		return nil
+	}
+	if !sum.s.equal(v.s) {
+		return &NonSquareCrossTermError{Sum: sum.signedSquare(), V: v.signedSquare(), Part: "radicand"}
+	}
+	sum.r = sum.r.add(v.r)
+	return nil
+}
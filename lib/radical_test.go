@@ -0,0 +1,94 @@
+package cg
+
+import (
+	"fmt"
+	"math/big"
+	"testing"
+)
+
+// Lowers col from its top cell with the coefficients as signed squares in big.Rat, the representation used before
+// radicals, and returns the signed squares of all cells.
+func lowerSignedSquares(col *column) ([][]*big.Rat, error) {
+	t := col.t
+	top := col.cells[0]
+	cur := make([]*big.Rat, top.size())
+	for k := range cur {
+		cur[k] = top.at(k).signedSquare()
+	}
+	rungs := [][]*big.Rat{cur}
+	for i := 0; i < len(col.cells)-1; i++ {
+		twom := col.twoj - 2*i
+		current, lower := col.cells[i], col.cells[i+1]
+		next := make([]*big.Rat, lower.size())
+		for l := range next {
+			twom1 := lower.twom1ForIndex(l)
+			twom2 := twom - 2 - twom1
+			sum := BlankRat()
+			if current.isGoodTwom1(twom1 + 2) {
+				r := big.NewRat(int64((t.twoj1+2+twom1)*(t.twoj1-twom1)), 4)
+				if err := addSignedSquares(sum, r.Mul(r, cur[(current.maxTwom1-twom1-2)/2])); err != nil {
+					return nil, err
+				}
+			}
+			if current.isGoodTwom1(twom1) {
+				r := big.NewRat(int64((t.twoj2+2+twom2)*(t.twoj2-twom2)), 4)
+				if err := addSignedSquares(sum, r.Mul(r, cur[(current.maxTwom1-twom1)/2])); err != nil {
+					return nil, err
+				}
+			}
+			next[l] = sum.Mul(sum, big.NewRat(4, int64((col.twoj+2-twom)*(col.twoj+twom))))
+		}
+		cur = next
+		rungs = append(rungs, cur)
+	}
+	return rungs, nil
+}
+
+// Checks lowering with radicals against the signed-square reference.
+func TestLowerMatchesSignedSquares(t *testing.T) {
+	const maxTwoj = 10
+	for twoj1 := 0; twoj1 <= maxTwoj; twoj1++ {
+		for twoj2 := 0; twoj2 <= twoj1; twoj2++ {
+			table, err := ComputeCGE(twoj1, twoj2)
+			if err != nil {
+				t.Fatalf("ComputeCGE(%v, %v): %v", twoj1, twoj2, err)
+			}
+			for _, col := range table.columns {
+				rungs, err := lowerSignedSquares(col)
+				if err != nil {
+					t.Fatalf("%v×%v: lowering column %v with signed squares: %v", twoj1, twoj2, col.dj, err)
+				}
+				for i, rung := range rungs {
+					for l, want := range rung {
+						if got := col.cells[i].at(l).signedSquare(); got.Cmp(want) != 0 {
+							t.Errorf("%v×%v: coefficient %v of cell %v of column %v has the signed square %v, want %v",
+								twoj1, twoj2, l, i, col.dj, got.RatString(), want.RatString())
+						}
+					}
+				}
+			}
+		}
+	}
+}
+
+// Times lowering all columns of a computed table from their top cells.
+func BenchmarkLower(b *testing.B) {
+	for _, twoj := range []int{50, 100, 200} {
+		b.Run(fmt.Sprintf("j=%v", FormatHalfInteger(twoj)), func(b *testing.B) {
+			t, err := ComputeCGE(twoj, twoj)
+			if err != nil {
+				b.Fatal(err)
+			}
+			b.ResetTimer()
+			for n := 0; n < b.N; n++ {
+				for _, col := range t.columns {
+					for i := 0; i < len(col.cells)-1; i++ {
+						if err := col.lower(i, nil); err != nil {
+							b.Fatal(err)
+						}
+					}
+				}
+			}
+		})
+	}
+}
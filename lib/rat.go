@@ -0,0 +1,186 @@
+package cg
+
+import (
+	"math"
+	"math/big"
+	"math/bits"
+)
+
+// An exact rational that stays in machine integers while its numerator and denominator fit in int64,
+// and is promoted to big.Rat only when an operation would overflow. Values are immutable, operations return new values.
+// The zero value is 0.
+type rat struct {
+	// Used when b is nil, in lowest terms with d > 0, except that the zero value has d = 0.
+	n int64
+	d int64
+	b *big.Rat
+}
+
+func ratFromInt(n int64) rat {
+	return rat{n: n, d: 1}
+}
+
+// Returns r as a rat, demoting it to machine integers when it fits.
+// Takes ownership of r, which must not be modified afterwards.
+func ratFromBig(r *big.Rat) rat {
+	if r.Num().IsInt64() && r.Denom().IsInt64() && r.Num().Int64() != math.MinInt64 {
+		return rat{n: r.Num().Int64(), d: r.Denom().Int64()}
+	}
+	return rat{b: r}
+}
+
+// Returns n/d for d > 0, reduced to lowest terms.
+func newSmallRat(n, d int64) rat {
+	if n == 0 {
+		return rat{}
+	}
+	g := gcd64(abs64(n), d)
+	return rat{n: n / g, d: d / g}
+}
+
+// Returns the value as a big.Rat, which the caller may modify.
+func (x rat) toBig() *big.Rat {
+	if x.b != nil {
+		return BlankRat().Set(x.b)
+	}
+	if x.n == 0 {
+		return BlankRat()
+	}
+	return big.NewRat(x.n, x.d)
+}
+
+// Returns the value as a big.Rat without copying, callers must not modify it.
+func (x rat) big() *big.Rat {
+	if x.b != nil {
+		return x.b
+	}
+	return x.toBig()
+}
+
+func (x rat) sign() int {
+	if x.b != nil {
+		return x.b.Sign()
+	}
+	switch {
+	case x.n < 0:
+		return -1
+	case x.n > 0:
+		return 1
+	}
+	return 0
+}
+
+func (x rat) neg() rat {
+	if x.b != nil {
+		return rat{b: BlankRat().Neg(x.b)}
+	}
+	return rat{n: -x.n, d: x.d}
+}
+
+func (x rat) add(o rat) rat {
+	if x.b == nil && o.b == nil {
+		if x.n == 0 {
+			return o
+		}
+		if o.n == 0 {
+			return x
+		}
+		// a/b+c/d=(a(d/g)+c(b/g))/(b(d/g)) with g=gcd(b,d).
+		g := gcd64(x.d, o.d)
+		if n1, ok := mul64(x.n, o.d/g); ok {
+			if n2, ok := mul64(o.n, x.d/g); ok {
+				if n, ok := add64(n1, n2); ok {
+					if d, ok := mul64(x.d, o.d/g); ok {
+						return newSmallRat(n, d)
+					}
+				}
+			}
+		}
+	}
+	return ratFromBig(BlankRat().Add(x.big(), o.big()))
+}
+
+func (x rat) mul(o rat) rat {
+	if x.b == nil && o.b == nil {
+		if x.n == 0 || o.n == 0 {
+			return rat{}
+		}
+		// Cross-reduce first so the products stay small.
+		g1 := gcd64(abs64(x.n), o.d)
+		g2 := gcd64(abs64(o.n), x.d)
+		if n, ok := mul64(x.n/g1, o.n/g2); ok {
+			if d, ok := mul64(x.d/g2, o.d/g1); ok {
+				return rat{n: n, d: d}
+			}
+		}
+	}
+	return ratFromBig(BlankRat().Mul(x.big(), o.big()))
+}
+
+// Returns x/o, o must not be zero.
+func (x rat) quo(o rat) rat {
+	return x.mul(o.inv())
+}
+
+func (x rat) inv() rat {
+	if x.b != nil {
+		return ratFromBig(BlankRat().Inv(x.b))
+	}
+	if x.n < 0 {
+		return rat{n: -x.d, d: -x.n}
+	}
+	return rat{n: x.d, d: x.n}
+}
+
+func (x rat) mulInt(n int64) rat {
+	if n == 1 {
+		return x
+	}
+	return x.mul(ratFromInt(n))
+}
+
+func (x rat) quoInt(n int64) rat {
+	if n == 1 {
+		return x
+	}
+	return x.mul(ratFromInt(n).inv())
+}
+
+func (x rat) String() string {
+	return x.big().String()
+}
+
+func abs64(n int64) int64 {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+func gcd64(a, b int64) int64 {
+	for b != 0 {
+		a, b = b, a%b
+	}
+	return a
+}
+
+// Multiplies with overflow check, MinInt64 is treated as overflow so negation is always safe.
+func mul64(a, b int64) (int64, bool) {
+	hi, lo := bits.Mul64(uint64(abs64(a)), uint64(abs64(b)))
+	if hi != 0 || lo > math.MaxInt64 || a == math.MinInt64 || b == math.MinInt64 {
+		return 0, false
+	}
+	if (a < 0) != (b < 0) {
+		return -int64(lo), true
+	}
+	return int64(lo), true
+}
+
+// Adds with overflow check, MinInt64 is treated as overflow so negation is always safe.
+func add64(a, b int64) (int64, bool) {
+	s := a + b
+	if (a > 0 && b > 0 && s < 0) || (a < 0 && b < 0 && s >= 0) || s == math.MinInt64 {
+		return 0, false
+	}
+	return s, true
+}
@@ -0,0 +1,108 @@
+package cg
+
+import (
+	"math"
+	"math/big"
+	"testing"
+)
+
+// Values around the int64 limits, each given as a numerator and denominator string.
+var ratTestValues = [][2]string{
+	{"0", "1"},
+	{"1", "1"},
+	{"-7", "3"},
+	{"9223372036854775807", "1"},  // MaxInt64.
+	{"-9223372036854775807", "1"}, // -MaxInt64.
+	{"9223372036854775806", "1"},
+	{"-9223372036854775808", "1"}, // MinInt64 stays in big.Rat.
+	{"9223372036854775808", "1"},  // MaxInt64+1.
+	{"1", "9223372036854775807"},
+	{"-1", "9223372036854775807"},
+	{"9223372036854775807", "9223372036854775806"},
+	{"4611686018427387904", "3"},  // 2^62.
+	{"-3037000499", "3037000493"}, // Products near MaxInt64.
+	{"18446744073709551617", "2"}, // 2^64+1.
+	{"1", "9223372036854775808"},
+}
+
+// Returns x as a big.Rat after checking that it is demoted to int64 exactly when it fits, and is in lowest terms.
+func checkRat(t *testing.T, op string, x rat) *big.Rat {
+	t.Helper()
+	v := x.big()
+	fits := v.Num().IsInt64() && v.Denom().IsInt64() && v.Num().Int64() != math.MinInt64
+	switch {
+	case x.b == nil && x.n == 0:
+	case x.b == nil && (x.d <= 0 || gcd64(abs64(x.n), x.d) != 1):
+		t.Errorf("%v = %v/%v is not in lowest terms", op, x.n, x.d)
+	case x.b != nil && fits:
+		t.Errorf("%v = %v was not demoted to int64", op, v.RatString())
+	}
+	return v
+}
+
+func TestRatMatchesBig(t *testing.T) {
+	values := make([]*big.Rat, len(ratTestValues))
+	for i, nd := range ratTestValues {
+		var ok bool
+		if values[i], ok = BlankRat().SetString(nd[0] + "/" + nd[1]); !ok {
+			t.Fatalf("invalid test value %v/%v", nd[0], nd[1])
+		}
+	}
+	for _, xv := range values {
+		x := ratFromBig(BlankRat().Set(xv))
+		if got := checkRat(t, "ratFromBig("+xv.RatString()+")", x); got.Cmp(xv) != 0 {
+			t.Errorf("ratFromBig(%v) = %v", xv.RatString(), got.RatString())
+		}
+		if got, want := checkRat(t, "-("+xv.RatString()+")", x.neg()), BlankRat().Neg(xv); got.Cmp(want) != 0 {
+			t.Errorf("-(%v) = %v, want %v", xv.RatString(), got.RatString(), want.RatString())
+		}
+		if x.sign() != xv.Sign() {
+			t.Errorf("sign(%v) = %v", xv.RatString(), x.sign())
+		}
+		if xv.Sign() != 0 {
+			if got, want := checkRat(t, "1/("+xv.RatString()+")", x.inv()), BlankRat().Inv(xv); got.Cmp(want) != 0 {
+				t.Errorf("1/(%v) = %v, want %v", xv.RatString(), got.RatString(), want.RatString())
+			}
+		}
+		for _, yv := range values {
+			y := ratFromBig(BlankRat().Set(yv))
+			op := "(" + xv.RatString() + ") + (" + yv.RatString() + ")"
+			if got, want := checkRat(t, op, x.add(y)), BlankRat().Add(xv, yv); got.Cmp(want) != 0 {
+				t.Errorf("%v = %v, want %v", op, got.RatString(), want.RatString())
+			}
+			op = "(" + xv.RatString() + ") × (" + yv.RatString() + ")"
+			if got, want := checkRat(t, op, x.mul(y)), BlankRat().Mul(xv, yv); got.Cmp(want) != 0 {
+				t.Errorf("%v = %v, want %v", op, got.RatString(), want.RatString())
+			}
+			if yv.Sign() != 0 {
+				op = "(" + xv.RatString() + ") / (" + yv.RatString() + ")"
+				if got, want := checkRat(t, op, x.quo(y)), BlankRat().Quo(xv, yv); got.Cmp(want) != 0 {
+					t.Errorf("%v = %v, want %v", op, got.RatString(), want.RatString())
+				}
+			}
+		}
+	}
+}
+
+func TestMul64Add64(t *testing.T) {
+	values := []int64{0, 1, -1, 2, -2, 3037000499, -3037000499, 3037000500, math.MaxInt64 / 2, -math.MaxInt64 / 2,
+		math.MaxInt64 - 1, math.MaxInt64, -math.MaxInt64, math.MinInt64}
+	inRange := func(z *big.Int) bool {
+		return z.IsInt64() && z.Int64() != math.MinInt64
+	}
+	for _, a := range values {
+		for _, b := range values {
+			ab, bb := big.NewInt(a), big.NewInt(b)
+			want := BlankInt().Mul(ab, bb)
+			wantOK := inRange(want) && a != math.MinInt64 && b != math.MinInt64
+			if got, ok := mul64(a, b); ok != wantOK || ok && got != want.Int64() {
+				t.Errorf("mul64(%v, %v) = %v, %v, want %v, %v", a, b, got, ok, want, wantOK)
+			}
+			want = BlankInt().Add(ab, bb)
+			wantOK = inRange(want)
+			if got, ok := add64(a, b); ok != wantOK || ok && got != want.Int64() {
+				t.Errorf("add64(%v, %v) = %v, %v, want %v, %v", a, b, got, ok, want, wantOK)
+			}
+		}
+	}
+}
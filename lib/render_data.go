@@ -1,19 +1,63 @@
 package cg
 
-type tableData struct {
-	J1       string
-	J2       string
-	Sections []*sectionData
+// TemplateDataVersion is the version of the data model that HTML templates run against, see TableData.
+// Fields may be added within a version, renaming or removing a field or changing its meaning increments it.
+const TemplateDataVersion = 1
+
+// TableData is the data model of HTML templates, see ParseHTMLTemplate.
+// Like the table of DefaultHTMLTemplate, it is made of sections of decreasing m, each a block of rows of decreasing m1,
+// with the coefficients of each row in columns of decreasing j. All quantum numbers are formatted like "-3/2".
+type TableData struct {
+	// TemplateDataVersion of the model.
+	Version int
+	// j1 and j2 in the order used to create the table.
+	J1 string
+	J2 string
+	// All j from j1+j2 down to |j1-j2|, the headings of the columns.
+	J        []string
+	Sections []*SectionData
 }
 
-type sectionData struct {
-	M            string
+// SectionData is the block of rows of one m.
+type SectionData struct {
+	M string
+	// Whether j = M is one of the columns, in which case the column starts with this section,
+	// as m >= 0 is decreasing. DefaultHTMLTemplate prints the heading of the column at this point.
 	PrintHeading bool
-	Rows         []*rowData
+	Rows         []*RowData
+
+	// Values behind the strings, for renderers other than the HTML template.
+	m HalfInteger
 }
 
-type rowData struct {
-	M1     string
-	M2     string
+// RowData holds the coefficients ⟨j1,m1;j2,m2|j,m⟩ of one m1 and m2, in the columns from j1+j2 down to the smallest j >= |m|.
+// Columns of smaller j, which cannot have this m, are left out.
+type RowData struct {
+	M1 string
+	M2 string
+	// The signed squares of the coefficients, e.g., "-2/5", see Table.Query.
 	Values []string
+
+	m1     HalfInteger
+	m2     HalfInteger
+	coeffs []SignedSqrt
+}
+
+// Decimals returns the coefficients as the nearest float64, e.g., -0.6324555320336759.
+// Like Radicals it is computed when called, so renderers and templates that do not use it pay nothing.
+func (r *RowData) Decimals() []float64 {
+	ret := make([]float64, len(r.coeffs))
+	for i, c := range r.coeffs {
+		ret[i] = c.Float64()
+	}
+	return ret
+}
+
+// Radicals returns the coefficients in simplified radical form, e.g., "-√10/5", see SignedSqrt.String.
+func (r *RowData) Radicals() []string {
+	ret := make([]string, len(r.coeffs))
+	for i, c := range r.coeffs {
+		ret[i] = c.String()
+	}
+	return ret
 }
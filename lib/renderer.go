@@ -0,0 +1,133 @@
+package cg
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"sync"
+)
+
+// Renderer renders a table to w in some output format.
+type Renderer interface {
+	Render(t *Table, w io.Writer) error
+}
+
+// RendererFunc adapts a function, such as the method expression (*Table).WriteHTML, to a Renderer.
+type RendererFunc func(t *Table, w io.Writer) error
+
+// Render calls f(t, w).
+func (f RendererFunc) Render(t *Table, w io.Writer) error {
+	return f(t, w)
+}
+
+// MultiRenderer is implemented by renderers that can also lay out several tables on one page, such as pdg-html.
+type MultiRenderer interface {
+	Renderer
+	RenderTables(w io.Writer, tables ...*Table) error
+}
+
+// MultiRendererFunc adapts a function laying out several tables, such as WritePDGHTML, to a MultiRenderer
+// that renders a single table as a page of one.
+type MultiRendererFunc func(w io.Writer, tables ...*Table) error
+
+// Render calls f(w, t).
+func (f MultiRendererFunc) Render(t *Table, w io.Writer) error {
+	return f(w, t)
+}
+
+// RenderTables calls f(w, tables...).
+func (f MultiRendererFunc) RenderTables(w io.Writer, tables ...*Table) error {
+	return f(w, tables...)
+}
+
+// A renderer and the file extension of its output.
+type registeredRenderer struct {
+	r   Renderer
+	ext string
+}
+
+var (
+	renderersMu sync.RWMutex
+	renderers   = make(map[string]registeredRenderer)
+)
+
+// RegisterRenderer makes a renderer available by the format name, with output files named with the given extension.
+// It panics if the name is already registered, so packages can add formats but not silently replace them.
+// Renderers that also implement MultiRenderer can lay out several tables on one page.
+//
+// The formats registered by this package are html, json, cgtb, csv, tsv, tex, tex-fragment, md, mathematica, sympy, julia, npy, npz, pdg-html and pdg-svg.
+func RegisterRenderer(name, ext string, r Renderer) {
+	renderersMu.Lock()
+	defer renderersMu.Unlock()
+	if _, dup := renderers[name]; dup {
+		panic(fmt.Sprintf("cg: renderer %q registered twice", name))
+	}
+	renderers[name] = registeredRenderer{r: r, ext: ext}
+}
+
+// LookupRenderer returns the renderer registered for the format name and the file extension of its output.
+func LookupRenderer(name string) (Renderer, string, bool) {
+	renderersMu.RLock()
+	defer renderersMu.RUnlock()
+	rr, ok := renderers[name]
+	return rr.r, rr.ext, ok
+}
+
+// RendererNames returns the names of all registered formats, sorted.
+func RendererNames() []string {
+	renderersMu.RLock()
+	defer renderersMu.RUnlock()
+	names := make([]string, 0, len(renderers))
+	for name := range renderers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// WriteOutput calls render with the file name, with stdout if name is "-", or with a new file in the temp directory
+// named after pattern like os.CreateTemp if name is empty, so that concurrent runs do not overwrite each other.
+// It returns the name of the file written, or "-" for stdout.
+// Failures to create or close the file are reported as *WriteError, failures of render as returned.
+func WriteOutput(name, pattern string, render func(w io.Writer) error) (string, error) {
+	var f *os.File
+	var err error
+	switch name {
+	case "-":
+		return name, render(os.Stdout)
+	case "":
+		f, err = os.CreateTemp("", pattern)
+	default:
+		f, err = os.Create(name)
+	}
+	if err != nil {
+		return "", &WriteError{Err: err}
+	}
+	defer f.Close()
+	if err := render(f); err != nil {
+		return "", err
+	}
+	if err := f.Close(); err != nil {
+		return "", &WriteError{Err: err}
+	}
+	return f.Name(), nil
+}
+
+func init() {
+	RegisterRenderer("html", "html", defaultHTMLRenderer)
+	RegisterRenderer("json", "json", RendererFunc((*Table).WriteJSON))
+	RegisterRenderer("cgtb", "cgtb", RendererFunc(func(t *Table, w io.Writer) error { return EncodeBinary(w, t) }))
+	RegisterRenderer("csv", "csv", RendererFunc(func(t *Table, w io.Writer) error { return t.WriteCSV(w, nil) }))
+	RegisterRenderer("tsv", "tsv", RendererFunc(func(t *Table, w io.Writer) error { return t.WriteCSV(w, &CSVOptions{Comma: '\t'}) }))
+	RegisterRenderer("tex", "tex", RendererFunc((*Table).WriteLaTeX))
+	RegisterRenderer("tex-fragment", "tex", RendererFunc((*Table).WriteLaTeXFragment))
+	RegisterRenderer("md", "md", RendererFunc((*Table).WriteMarkdown))
+	RegisterRenderer("mathematica", "wl", RendererFunc(func(t *Table, w io.Writer) error { return t.WriteCAS(w, Mathematica) }))
+	RegisterRenderer("sympy", "py", RendererFunc(func(t *Table, w io.Writer) error { return t.WriteCAS(w, SymPy) }))
+	RegisterRenderer("julia", "jl", RendererFunc(func(t *Table, w io.Writer) error { return t.WriteCAS(w, Julia) }))
+	RegisterRenderer("npy", "npy", RendererFunc((*Table).WriteNPY))
+	RegisterRenderer("npz", "npz", RendererFunc((*Table).WriteNPZ))
+	RegisterRenderer("pdg-html", "html", MultiRendererFunc(WritePDGHTML))
+	RegisterRenderer("pdg-svg", "svg", MultiRendererFunc(WritePDGSVG))
+}
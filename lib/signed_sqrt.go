@@ -0,0 +1,255 @@
+package cg
+
+import (
+	"fmt"
+	"math/big"
+	"sync"
+)
+
+// SignedSqrt is an exact real number of the form sign×√(p/q) with p/q a non-negative rational.
+// Every CG coefficient takes this form. The zero value is 0.
+type SignedSqrt struct {
+	// The signed square sign×p/q, nil means 0.
+	sq *big.Rat
+}
+
+// NewSignedSqrt returns the number whose signed square is the given value, i.e., sign(r)×√|r|.
+func NewSignedSqrt(signedSquare *big.Rat) SignedSqrt {
+	if signedSquare.Sign() == 0 {
+		return SignedSqrt{}
+	}
+	return SignedSqrt{sq: BlankRat().Set(signedSquare)}
+}
+
+// Returns the signed square without copying, callers must not modify it.
+func (s SignedSqrt) signedSquare() *big.Rat {
+	if s.sq == nil {
+		return BlankRat()
+	}
+	return s.sq
+}
+
+// SignedSquare returns sign×p/q, which is the representation returned by Table.Query.
+func (s SignedSqrt) SignedSquare() *big.Rat {
+	return BlankRat().Set(s.signedSquare())
+}
+
+// Square returns the square p/q.
+func (s SignedSqrt) Square() *big.Rat {
+	return BlankRat().Abs(s.signedSquare())
+}
+
+// Sign returns -1, 0 or +1 depending on the sign of s.
+func (s SignedSqrt) Sign() int {
+	return s.signedSquare().Sign()
+}
+
+// IsZero reports whether s is 0.
+func (s SignedSqrt) IsZero() bool {
+	return s.Sign() == 0
+}
+
+// Neg returns -s.
+func (s SignedSqrt) Neg() SignedSqrt {
+	return SignedSqrt{sq: BlankRat().Neg(s.signedSquare())}
+}
+
+// Mul returns the exact product s×o.
+func (s SignedSqrt) Mul(o SignedSqrt) SignedSqrt {
+	return NewSignedSqrt(BlankRat().Mul(s.signedSquare(), o.signedSquare()))
+}
+
+// Quo returns the exact quotient s/o. It panics if o is 0.
+func (s SignedSqrt) Quo(o SignedSqrt) SignedSqrt {
+	return NewSignedSqrt(BlankRat().Quo(s.signedSquare(), o.signedSquare()))
+}
+
+// Add returns the exact sum s+o, which is only representable when the radicands of s and o match,
+// i.e., when s×o is rational. Otherwise a *NonSquareCrossTermError is returned.
+func (s SignedSqrt) Add(o SignedSqrt) (SignedSqrt, error) {
+	sum := s.SignedSquare()
+	if err := addSignedSquares(sum, o.signedSquare()); err != nil {
+		return SignedSqrt{}, err
+	}
+	return NewSignedSqrt(sum), nil
+}
+
+// Cmp compares s and o and returns -1, 0 or +1.
+func (s SignedSqrt) Cmp(o SignedSqrt) int {
+	// sign(x)×√|x| is monotonic in x, so comparing signed squares suffices.
+	return s.signedSquare().Cmp(o.signedSquare())
+}
+
+// Float64 returns the nearest float64 value of s.
+func (s SignedSqrt) Float64() float64 {
+	f, _ := s.BigFloat(64).Float64()
+	return f
+}
+
+// BigFloat returns the value of s rounded to the given precision in bits.
+func (s SignedSqrt) BigFloat(prec uint) *big.Float {
+	f := new(big.Float).SetPrec(prec).SetRat(s.Square())
+	f.Sqrt(f)
+	if s.Sign() < 0 {
+		f.Neg(f)
+	}
+	return f
+}
+
+// Returns the simplified radical form sign×a√b/c with b square-free.
+func (s SignedSqrt) radical() (sign int, a, b, c *big.Int) {
+	sq := s.Square()
+	// √(p/q)=√(pq)/q.
+	a, b = squareFree(BlankInt().Mul(sq.Num(), sq.Denom()))
+	c = BlankInt().Set(sq.Denom())
+	g := BlankInt().GCD(nil, nil, a, c)
+	a.Quo(a, g)
+	c.Quo(c, g)
+	return s.Sign(), a, b, c
+}
+
+// String formats s in simplified radical form, e.g., "-√6/3", "2√3", "1/2".
+func (s SignedSqrt) String() string {
+	if s.IsZero() {
+		return "0"
+	}
+	sign, a, b, c := s.radical()
+	str := ""
+	if sign < 0 {
+		str += "-"
+	}
+	if !isOne(a) || isOne(b) {
+		str += a.String()
+	}
+	if !isOne(b) {
+		str += "√" + b.String()
+	}
+	if !isOne(c) {
+		str += "/" + c.String()
+	}
+	return str
+}
+
+// LaTeX formats s in simplified radical form for LaTeX math mode, e.g., "-\frac{\sqrt{6}}{3}".
+func (s SignedSqrt) LaTeX() string {
+	if s.IsZero() {
+		return "0"
+	}
+	sign, a, b, c := s.radical()
+	str := ""
+	if sign < 0 {
+		str += "-"
+	}
+	num := ""
+	if !isOne(a) || isOne(b) {
+		num += a.String()
+	}
+	if !isOne(b) {
+		num += fmt.Sprintf("\\sqrt{%v}", b)
+	}
+	if isOne(c) {
+		return str + num
+	}
+	return str + fmt.Sprintf("\\frac{%v}{%v}", num, c)
+}
+
+// Adds v onto sum (both are to be interpreted as square of the underlying rational values with sign on the numerator).
+// Unlike accum on radicals, this needs to check the cross term is a perfect square.
+func addSignedSquares(sum, v *big.Rat) error {
+	// Determine the overall sign.
+	n1 := BlankInt().Mul(sum.Num(), v.Denom())
+	n2 := BlankInt().Mul(sum.Denom(), v.Num())
+	overallSign := n1.Add(n1, n2).Sign()
+
+	if overallSign == 0 {
+		sum.SetFrac64(0, 1)
+		return nil
+	}
+
+	abs1 := BlankRat().Abs(sum)
+	abs2 := BlankRat().Abs(v)
+
+	// Cross term.
+	cross := BlankRat().Mul(abs1, abs2)
+	// Verify cross term is perfect square of rational.
+	// Note this is a much stronger condition than the condition that CG coefficients themselves are rational squares.
+	numRoot := BlankInt().Sqrt(cross.Num())
+	r := BlankInt().Mul(numRoot, numRoot)
+	if r.Cmp(cross.Num()) != 0 {
+		return &NonSquareCrossTermError{Sum: BlankRat().Set(sum), V: BlankRat().Set(v), Part: "numerator"}
+	}
+	denomRoot := BlankInt().Sqrt(cross.Denom())
+	r = r.Mul(denomRoot, denomRoot)
+	if r.Cmp(cross.Denom()) != 0 {
+		return &NonSquareCrossTermError{Sum: BlankRat().Set(sum), V: BlankRat().Set(v), Part: "denominator"}
+	}
+	cross = cross.SetFrac(numRoot, denomRoot)
+	crossFactor := big.NewRat(2*int64(sum.Num().Sign()*v.Num().Sign()), 1)
+
+	// Add to sum the sum of squares and cross term with proper sign and factor 2.
+	sum.Add(abs1, abs2).Add(sum, cross.Mul(cross, crossFactor))
+
+	// Apply the overall sign.
+	if overallSign < 0 {
+		sum.Neg(sum)
+	}
+	return nil
+}
+
+func isOne(n *big.Int) bool {
+	return n.IsInt64() && n.Int64() == 1
+}
+
+var (
+	smallPrimesOnce sync.Once
+	smallPrimes     []int64
+)
+
+// Returns the primes below 2^16 for trial division.
+func getSmallPrimes() []int64 {
+	smallPrimesOnce.Do(func() {
+		const limit = 1 << 16
+		composite := make([]bool, limit)
+		for p := 2; p < limit; p++ {
+			if composite[p] {
+				continue
+			}
+			smallPrimes = append(smallPrimes, int64(p))
+			for q := p * p; q < limit; q += p {
+				composite[q] = true
+			}
+		}
+	})
+	return smallPrimes
+}
+
+// Factors n=a²b for non-negative n. b is square-free unless n has repeated prime factors beyond the trial division bound.
+func squareFree(n *big.Int) (a, b *big.Int) {
+	a, b = big.NewInt(1), BlankInt().Set(n)
+	if b.Sign() == 0 {
+		return a, b
+	}
+	p, p2, q, r := BlankInt(), BlankInt(), BlankInt(), BlankInt()
+	for _, prime := range getSmallPrimes() {
+		p.SetInt64(prime)
+		if p2.Mul(p, p).Cmp(b) > 0 {
+			// No square of this or a larger prime fits in the remainder.
+			return a, b
+		}
+		// Take out p² as long as it divides b.
+		for {
+			q.QuoRem(b, p2, r)
+			if r.Sign() != 0 {
+				break
+			}
+			b.Set(q)
+			a.Mul(a, p)
+		}
+	}
+	// Large prime factors remain, catch the case where they form a perfect square.
+	if root := BlankInt().Sqrt(b); q.Mul(root, root).Cmp(b) == 0 {
+		a.Mul(a, root)
+		b.SetInt64(1)
+	}
+	return a, b
+}
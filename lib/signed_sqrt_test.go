@@ -0,0 +1,76 @@
+package cg
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+)
+
+func TestSignedSqrtFormat(t *testing.T) {
+	for _, tc := range []struct {
+		signedSquare string
+		str, latex   string
+	}{
+		{"0", "0", "0"},
+		{"1", "1", "1"},
+		{"-1/4", "-1/2", "-\\frac{1}{2}"},
+		{"2/3", "√6/3", "\\frac{\\sqrt{6}}{3}"},
+		{"-2/3", "-√6/3", "-\\frac{\\sqrt{6}}{3}"},
+		{"12", "2√3", "2\\sqrt{3}"},
+		{"1/2", "√2/2", "\\frac{\\sqrt{2}}{2}"},
+		{"-5", "-√5", "-\\sqrt{5}"},
+		{"9/50", "3√2/10", "\\frac{3\\sqrt{2}}{10}"},
+	} {
+		sq, _ := BlankRat().SetString(tc.signedSquare)
+		s := NewSignedSqrt(sq)
+		if got := s.String(); got != tc.str {
+			t.Errorf("String of signed square %v = %q, want %q", tc.signedSquare, got, tc.str)
+		}
+		if got := s.LaTeX(); got != tc.latex {
+			t.Errorf("LaTeX of signed square %v = %q, want %q", tc.signedSquare, got, tc.latex)
+		}
+	}
+}
+
+func TestSignedSqrtAdd(t *testing.T) {
+	for _, tc := range []struct {
+		a, b, sum string
+	}{
+		// √2+√8 = 3√2.
+		{"2", "8", "18"},
+		// √2-√8 = -√2.
+		{"2", "-8", "-2"},
+		// √(1/2)-√(1/2) = 0.
+		{"1/2", "-1/2", "0"},
+		{"0", "-2/3", "-2/3"},
+		// √(2/3)+√(1/6) = 3/√6.
+		{"2/3", "1/6", "3/2"},
+	} {
+		a, _ := BlankRat().SetString(tc.a)
+		b, _ := BlankRat().SetString(tc.b)
+		want, _ := BlankRat().SetString(tc.sum)
+		got, err := NewSignedSqrt(a).Add(NewSignedSqrt(b))
+		if err != nil {
+			t.Errorf("Add of signed squares %v and %v: %v", tc.a, tc.b, err)
+			continue
+		}
+		if got.SignedSquare().Cmp(want) != 0 {
+			t.Errorf("Add of signed squares %v and %v = %v, want %v", tc.a, tc.b, got.SignedSquare().RatString(), tc.sum)
+		}
+	}
+}
+
+func TestSignedSqrtAddDifferentRadicands(t *testing.T) {
+	for _, tc := range []struct{ a, b *big.Rat }{
+		{big.NewRat(2, 1), big.NewRat(3, 1)},
+		{big.NewRat(1, 2), big.NewRat(-1, 3)},
+		{big.NewRat(5, 4), big.NewRat(5, 3)},
+	} {
+		_, err := NewSignedSqrt(tc.a).Add(NewSignedSqrt(tc.b))
+		var crossErr *NonSquareCrossTermError
+		if !errors.As(err, &crossErr) {
+			t.Errorf("Add of signed squares %v and %v: got %v, want a *NonSquareCrossTermError",
+				tc.a.RatString(), tc.b.RatString(), err)
+		}
+	}
+}
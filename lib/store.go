@@ -0,0 +1,174 @@
+package cg
+
+import (
+	"math/big"
+	"math/bits"
+	"sync"
+	"sync/atomic"
+)
+
+// Compact storage of the coefficients r√s of a table, so tables of large j fit in memory.
+//
+// Each coefficient has a slot in the flat arrays of its column, see column.n, addressed by the offset of its cell plus the index of m1.
+// A slot holds r as two int64 while it fits, and the words of the prime set s, of which all slots of a table have the same number.
+// Once r overflows, the words of its numerator and denominator are kept back to back in an arena shared by the table,
+// and the slot holds their position and lengths instead.
+type store struct {
+	// Words of the prime set of each slot, enough for all primes up to maxPrime.
+	radicandWords int
+
+	mu    sync.Mutex
+	words segments[big.Word]
+}
+
+// Sizes of the first and largest segments of the arena, it grows with the table up to large segments that keep the directory short.
+const (
+	firstWordSegment = 1 << 8
+	maxWordSegment   = 1 << 16
+)
+
+func newStore(maxPrime int) *store {
+	primes := 0
+	for _, p := range getSmallPrimes() {
+		if p > int64(maxPrime) {
+			break
+		}
+		primes++
+	}
+	return &store{
+		radicandWords: (primes + 63) / 64,
+		words:         segments[big.Word]{first: firstWordSegment, max: maxWordSegment},
+	}
+}
+
+// Copies the words of numerator and denominator into the arena and returns their position.
+func (st *store) saveWords(num, den []big.Word) int {
+	st.mu.Lock()
+	i := st.words.alloc(len(num) + len(den))
+	st.mu.Unlock()
+	w := st.words.run(i, len(num)+len(den))
+	copy(w, num)
+	copy(w[len(num):], den)
+	return i
+}
+
+// An append-only list of values in segments that double in size up to a maximum, so values never move once added.
+// Values can be read without the lock of the writer, as long as their index was handed over with proper synchronization.
+type segments[T any] struct {
+	// Sizes of the first and the largest segments, both powers of two.
+	first int
+	max   int
+	// Values allocated so far, including the ends of segments skipped to keep runs contiguous.
+	n   int
+	dir atomic.Pointer[[][]T]
+}
+
+// Returns the segment holding index i and the offset of i in it.
+func (s *segments[T]) locate(i int) (int, int) {
+	// Number and total size of the segments doubling in size.
+	k := bits.Len(uint(s.max / s.first))
+	total := s.first * (1<<k - 1)
+	if i >= total {
+		return k + (i-total)/s.max, (i - total) % s.max
+	}
+	seg := bits.Len(uint(i/s.first+1)) - 1
+	return seg, i - s.first*(1<<seg-1)
+}
+
+func (s *segments[T]) segmentSize(seg int) int {
+	if seg >= bits.Len(uint(s.max/s.first)) {
+		return s.max
+	}
+	return s.first << seg
+}
+
+// Allocates a contiguous run of n values and returns the index of the first one, n must not be larger than the maximum segment size.
+// Calls must be serialized by the caller.
+func (s *segments[T]) alloc(n int) int {
+	seg, off := s.locate(s.n)
+	for off+n > s.segmentSize(seg) {
+		s.n += s.segmentSize(seg) - off
+		seg, off = s.locate(s.n)
+	}
+	if off == 0 {
+		// Segments are added to a copy of the directory, readers may still be using the old one.
+		var dir [][]T
+		if old := s.dir.Load(); old != nil {
+			dir = append(dir, *old...)
+		}
+		for len(dir) <= seg {
+			dir = append(dir, nil)
+		}
+		dir[seg] = make([]T, s.segmentSize(seg))
+		s.dir.Store(&dir)
+	}
+	i := s.n
+	s.n += n
+	return i
+}
+
+// Returns the run of n values starting at index i.
+func (s *segments[T]) run(i, n int) []T {
+	seg, off := s.locate(i)
+	return (*s.dir.Load())[seg][off : off+n : off+n]
+}
+
+// Returns the coefficient in slot k of this column.
+func (col *column) load(k int) radical {
+	n, d := col.n[k], col.d[k]
+	if d == 0 {
+		return radical{}
+	}
+	// Prime sets are never modified, so the radical can share the words of the slot.
+	w := col.t.store.radicandWords
+	s := primeSet(col.s[k*w : (k+1)*w : (k+1)*w])
+	if d > 0 {
+		return radical{r: rat{n: n, d: d}, s: s}
+	}
+	numLen, denLen, neg := unpackWordLengths(d)
+	words := col.t.store.words.run(int(n), numLen+denLen)
+	// The denominator of a blank rat is set, so Denom returns a reference to it rather than a new Int.
+	r := BlankRat()
+	r.Num().SetBits(words[:numLen:numLen])
+	r.Denom().SetBits(words[numLen:])
+	if neg {
+		r.Num().Neg(r.Num())
+	}
+	return radical{r: rat{b: r}, s: s}
+}
+
+// Stores v in slot k of this column.
+func (col *column) save(k int, v radical) {
+	w := col.t.store.radicandWords
+	s := col.s[k*w : (k+1)*w]
+	// Words of v.s beyond the slot can only be zero, all primes are below maxPrime.
+	copy(s, v.s)
+	for i := len(v.s); i < w; i++ {
+		s[i] = 0
+	}
+	if v.isZero() {
+		col.n[k], col.d[k] = 0, 0
+		return
+	}
+	if v.r.b == nil {
+		col.n[k], col.d[k] = v.r.n, v.r.d
+		return
+	}
+	num, den := v.r.b.Num().Bits(), v.r.b.Denom().Bits()
+	col.n[k] = int64(col.t.store.saveWords(num, den))
+	col.d[k] = packWordLengths(len(num), len(den), v.r.sign() < 0)
+}
+
+// Packs the word lengths and sign of a big rational into a negative int64, telling it apart from a denominator.
+func packWordLengths(numLen, denLen int, neg bool) int64 {
+	meta := int64(numLen)<<32 | int64(denLen)<<1
+	if neg {
+		meta |= 1
+	}
+	return ^meta
+}
+
+func unpackWordLengths(d int64) (int, int, bool) {
+	meta := ^d
+	return int(meta >> 32), int(meta>>1) & (1<<31 - 1), meta&1 != 0
+}
@@ -1,11 +1,10 @@
 package cg
 
 import (
+	"context"
 	"fmt"
+	"io"
 	"math/big"
-	"os"
-	"path/filepath"
-	"sync"
 )
 
 // Table represents the table of the CG coefficient.
@@ -15,41 +14,103 @@ type Table struct {
 	twoj1     int
 	twoj2     int
 	columns   []*column
+	// Shared by the views of the table, see view.
+	store *store
 }
 
 // ComputeCG computes the CG table for the given j1 and j2.
 // Arguments are twice the value of actual j1 and j2 so they are integers.
+// Either j may be zero, in which case the table holds the single trivial coefficient 1 for each m.
+// It panics on error, see ComputeCGE for the error-returning variant.
 func ComputeCG(twoj1, twoj2 int) *Table {
-	if twoj1 <= 0 || twoj2 <= 0 {
-		panic(fmt.Sprintf("invalid j1 or j2: %v, %v", twoj1, twoj2))
+	t, err := ComputeCGE(twoj1, twoj2)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+// ComputeCGE computes the CG table for the given j1 and j2, like ComputeCG, but returns an error instead of panicking.
+// The error is one of *InvalidJError, *OrthonormalityError, *RadicandError or *NonSquareCrossTermError.
+func ComputeCGE(twoj1, twoj2 int) (*Table, error) {
+	return ComputeCGContext(context.Background(), twoj1, twoj2, nil)
+}
+
+// ComputeCGContext computes the CG table for the given j1 and j2, like ComputeCGE, with cancellation, progress reporting and limits.
+// When ctx is done, all column computations stop and ctx.Err() is returned. Options may be nil.
+// Besides the errors of ComputeCGE, the error may be *MemoryLimitError, or ErrPoolClosed if Options.Pool is closed.
+func ComputeCGContext(ctx context.Context, twoj1, twoj2 int, opts *Options) (*Table, error) {
+	if twoj1 < 0 || twoj2 < 0 {
+		return nil, &InvalidJError{TwoJ1: twoj1, TwoJ2: twoj2}
+	}
+	if opts == nil {
+		opts = &Options{}
+	}
+	if opts.MaxMemory > 0 {
+		if estimate := EstimateMemory(twoj1, twoj2); estimate > opts.MaxMemory {
+			return nil, &MemoryLimitError{Estimate: estimate, Limit: opts.MaxMemory}
+		}
 	}
 	exchanged := false
 	if twoj1 < twoj2 {
 		twoj1, twoj2 = twoj2, twoj1
 		exchanged = true
 	}
-	t := &Table{
-		exchanged: exchanged,
-		twoj1:     twoj1,
-		twoj2:     twoj2,
-		columns:   make([]*column, twoj2+1),
+	t := newTable(twoj1, twoj2)
+	t.exchanged = exchanged
+
+	pool := opts.Pool
+	if pool == nil {
+		pool = defaultPool()
+	}
+	prog := newProgress(opts.Progress)
+	prog.add(t.cellCount())
+	if err := startComputation(ctx, t, pool, prog).wait(); err != nil {
+		return nil, err
 	}
+	return t, nil
+}
 
+// Allocates the columns of the table for j1 >= j2, with all coefficients zero.
+func newTable(twoj1, twoj2 int) *Table {
+	t := &Table{
+		twoj1:   twoj1,
+		twoj2:   twoj2,
+		columns: make([]*column, twoj2+1),
+	}
+	t.store = newStore(t.maxPrime())
 	for dj := 0; dj <= twoj2; dj++ {
 		t.columns[dj] = newColumn(t, dj)
 	}
+	return t
+}
 
-	// One goroutine per column.
-	var wg sync.WaitGroup
-	wg.Add(twoj2 + 1)
+// Counts the cells |j,m⟩ with m >= 0 of the table, each computed in one step.
+func (t *Table) cellCount() int {
+	cells := 0
 	for _, col := range t.columns {
-		go func(c *column) {
-			c.compute()
-			wg.Done()
-		}(col)
+		cells += len(col.cells)
 	}
-	wg.Wait()
-	return t
+	return cells
+}
+
+// Returns a table sharing the coefficients of t, answering queries with j1 and j2 in the given order.
+// Tables are never modified once computed, so views can be handed out to concurrent readers.
+func (t *Table) view(exchanged bool) *Table {
+	v := *t
+	v.exchanged = exchanged
+	return &v
+}
+
+// ComputeTable computes the CG table for the given j1 and j2, like ComputeCGE.
+func ComputeTable(j1, j2 HalfInteger) (*Table, error) {
+	return ComputeCGE(j1.Twice(), j2.Twice())
+}
+
+// Returns a bound on the prime factors of the signed squares of the coefficients, which are products of factorial ratios
+// of numbers up to j1+j2+j+1.
+func (t *Table) maxPrime() int {
+	return t.twoj1 + t.twoj2 + 1
 }
 
 // Gets the cell representing state |j1+j2-dj,j1+j2-dm>.
@@ -57,23 +118,26 @@ func (t *Table) cell(dj, dm int) *cell {
 	return t.columns[dj].cells[dm-dj]
 }
 
-func (t *Table) RenderHTML() {
-	data := t.getTableData()
-	filename := filepath.Join(os.TempDir(), "clebsch-gordan.html")
-	f, err := os.Create(filename)
+// RenderHTML renders the table to a new HTML file in the temp directory and prints its path.
+// Each call creates its own file, so concurrent calls do not overwrite each other, see WriteHTML to choose the destination.
+func (t *Table) RenderHTML() error {
+	name, err := WriteOutput("", "clebsch-gordan-*.html", t.WriteHTML)
 	if err != nil {
-		panic(err)
-	}
-	defer f.Close()
-	if err := rootTmpl.Execute(f, data); err != nil {
-		panic(err)
+		return err
 	}
+	fmt.Println(name)
+	return nil
+}
 
-	fmt.Println(filename)
+// WriteHTML renders the table as an HTML page to w with DefaultHTMLTemplate.
+// Failures are reported as *WriteError.
+func (t *Table) WriteHTML(w io.Writer) error {
+	return defaultHTMLRenderer.Render(t, w)
 }
 
 // Query queries the CG table for the value
 // ⟨j1,m1;j2,m2|j,m⟩, where j1 and j2 are the same values (in this order) used to create this table.
+// The returned value is the signed square of the coefficient, see QuerySqrt for the coefficient itself.
 // All arguments are twice the actual values so they are integers.
 func (t *Table) Query(twoj, twom, twom1, twom2 int) *big.Rat {
 	return t.queryHelper(twoj, twom, twom1, twom2, false)
@@ -86,6 +150,26 @@ func (t *Table) ExchangedQuery(twoj, twom, twom1, twom2 int) *big.Rat {
 	return t.queryHelper(twoj, twom, twom1, twom2, true)
 }
 
+// QuerySqrt queries the CG table for the value ⟨j1,m1;j2,m2|j,m⟩, like Query, but returns the coefficient itself rather than its signed square.
+func (t *Table) QuerySqrt(twoj, twom, twom1, twom2 int) SignedSqrt {
+	return NewSignedSqrt(t.Query(twoj, twom, twom1, twom2))
+}
+
+// ExchangedQuerySqrt queries the CG table for the value ⟨j2,m2;j1,m1|j,m⟩, like ExchangedQuery, but returns the coefficient itself rather than its signed square.
+func (t *Table) ExchangedQuerySqrt(twoj, twom, twom1, twom2 int) SignedSqrt {
+	return NewSignedSqrt(t.ExchangedQuery(twoj, twom, twom1, twom2))
+}
+
+// QueryHalf queries the CG table for the value ⟨j1,m1;j2,m2|j,m⟩, like Query.
+func (t *Table) QueryHalf(j, m, m1, m2 HalfInteger) *big.Rat {
+	return t.Query(j.Twice(), m.Twice(), m1.Twice(), m2.Twice())
+}
+
+// ExchangedQueryHalf queries the CG table for the value ⟨j2,m2;j1,m1|j,m⟩, like ExchangedQuery.
+func (t *Table) ExchangedQueryHalf(j, m, m1, m2 HalfInteger) *big.Rat {
+	return t.ExchangedQuery(j.Twice(), m.Twice(), m1.Twice(), m2.Twice())
+}
+
 func (t *Table) queryHelper(twoj, twom, twom1, twom2 int, exchangedQuery bool) *big.Rat {
 	dj := t.twoj1 + t.twoj2 - twoj
 	if dj%2 != 0 {
@@ -99,6 +183,10 @@ func (t *Table) queryHelper(twoj, twom, twom1, twom2 int, exchangedQuery bool) *
 		return BlankRat()
 	}
 	col := t.columns[dj]
+	if col == nil {
+		// Not computed, see ComputeMultiplet.
+		return BlankRat()
+	}
 	mneg := false
 	if twom < 0 {
 		mneg = true
@@ -121,7 +209,7 @@ func (t *Table) queryHelper(twoj, twom, twom1, twom2 int, exchangedQuery bool) *
 	if !cell.isGoodTwom1(twom1) {
 		return BlankRat()
 	}
-	ret := BlankRat().Set(cell.get(twom1))
+	ret := cell.get(twom1).signedSquare()
 	// Use CG coefficient symmetry property:
 	// 1. ⟨j1,m1;j2,m2|j,m⟩=(-1)^{j1+j2-j}⟨j2,m2;j1,m1|j,m⟩
 	// 2. ⟨j1,-m1;j2,-m2|j,-m⟩=(-1)^{j1+j2-j}⟨j1,m1;j2,m2|j,m⟩
@@ -131,21 +219,24 @@ func (t *Table) queryHelper(twoj, twom, twom1, twom2 int, exchangedQuery bool) *
 	return ret
 }
 
-func (t *Table) getTableData() *tableData {
-	twoj1, twoj2 := t.twoj1, t.twoj2
-	if t.exchanged {
-		twoj1, twoj2 = twoj2, twoj1
+// TemplateData returns the data model of the table for HTML templates, see ParseHTMLTemplate.
+func (t *Table) TemplateData() *TableData {
+	js := make([]string, len(t.columns))
+	for dj := range t.columns {
+		js[dj] = FormatHalfInteger(t.twoj1 + t.twoj2 - 2*dj)
 	}
-	return &tableData{
-		J1:       FormatHalfInteger(twoj1),
-		J2:       FormatHalfInteger(twoj2),
+	return &TableData{
+		Version:  TemplateDataVersion,
+		J1:       t.J1().String(),
+		J2:       t.J2().String(),
+		J:        js,
 		Sections: t.getSectionsData(),
 	}
 }
 
-func (t *Table) getSectionsData() []*sectionData {
+func (t *Table) getSectionsData() []*SectionData {
 	col0 := t.columns[0]
-	data := make([]*sectionData, 0, col0.twoj+1)
+	data := make([]*SectionData, 0, col0.twoj+1)
 	for i := range col0.cells {
 		data = append(data, t.getSectionData(i, false))
 	}
@@ -160,28 +251,33 @@ func (t *Table) getSectionsData() []*sectionData {
 	return data
 }
 
-func (t *Table) getSectionData(i int, mirrored bool) *sectionData {
+func (t *Table) getSectionData(i int, mirrored bool) *SectionData {
 	col0 := t.columns[0]
 	twom := col0.twoj - 2*i
 	mStr := FormatHalfInteger(twom)
-	data := &sectionData{
+	data := &SectionData{
 		M:            mStr,
 		PrintHeading: !mirrored && twom >= (t.twoj1-t.twoj2),
-		Rows:         make([]*rowData, 0, len(col0.cells[i].c)),
+		Rows:         make([]*RowData, 0, col0.cells[i].size()),
+		m:            NewHalfInteger(twom),
 	}
 	if mirrored {
 		data.M = FormatHalfInteger(-twom)
+		data.m = NewHalfInteger(-twom)
 	}
-	for l := range col0.cells[i].c {
+	for l := 0; l < col0.cells[i].size(); l++ {
 		twom1 := col0.cells[i].twom1ForIndex(l)
 		twom2 := twom - twom1
 		if t.exchanged {
 			twom1, twom2 = twom2, twom1
 		}
-		row := &rowData{
+		row := &RowData{
 			M1:     FormatHalfInteger(twom1),
 			M2:     FormatHalfInteger(twom2),
 			Values: make([]string, 0, i+1),
+			m1:     NewHalfInteger(twom1),
+			m2:     NewHalfInteger(twom2),
+			coeffs: make([]SignedSqrt, 0, i+1),
 		}
 		if mirrored {
 			if twom1 != 0 {
@@ -190,19 +286,20 @@ func (t *Table) getSectionData(i int, mirrored bool) *sectionData {
 			if twom2 != 0 {
 				row.M2 = FormatHalfInteger(-twom2)
 			}
+			row.m1, row.m2 = row.m1.Neg(), row.m2.Neg()
 		}
 		for dj := 0; dj < i+1 && dj < len(t.columns); dj++ {
 			col := t.columns[dj]
-			value := ""
+			value := col.cells[i-dj].at(l)
 			// Use CG coefficient symmetry property:
 			// 1. ⟨j1,m1;j2,m2|j,m⟩=(-1)^{j1+j2-j}⟨j2,m2;j1,m1|j,m⟩
 			// 2. ⟨j1,-m1;j2,-m2|j,-m⟩=(-1)^{j1+j2-j}⟨j1,m1;j2,m2|j,m⟩
 			if (t.exchanged != mirrored) && dj%2 != 0 {
-				value = FormatRat(BlankRat().Neg(col.cells[i-dj].c[l]))
-			} else {
-				value = FormatRat(col.cells[i-dj].c[l])
+				value = value.neg()
 			}
-			row.Values = append(row.Values, value)
+			square := value.signedSquare()
+			row.Values = append(row.Values, FormatRat(square))
+			row.coeffs = append(row.coeffs, NewSignedSqrt(square))
 		}
 		data.Rows = append(data.Rows, row)
 	}
@@ -0,0 +1,117 @@
+package cg
+
+import (
+	"context"
+	"sync"
+)
+
+// TableSet holds the CG tables for all j1 and j2 up to a maximum, see ComputeAll.
+type TableSet struct {
+	maxTwoj int
+	// Tables with j1 >= j2, see index.
+	tables []*Table
+}
+
+// ComputeAll computes the CG tables for all j1 and j2 from 0 to maxTwoj/2 in steps of 1/2, like ComputeAllContext with a background context.
+func ComputeAll(maxTwoj int, opts *Options) (*TableSet, error) {
+	return ComputeAllContext(context.Background(), maxTwoj, opts)
+}
+
+// ComputeAllContext computes the CG tables for all j1 and j2 from 0 to maxTwoj/2 in steps of 1/2.
+// The argument is twice the value of actual maximum j so it is an integer.
+// Tables for (j1, j2) and (j2, j1) share their coefficients, so each pair is only computed once.
+// All tables are computed at the same time on one pool, and Options.Progress reports the cells of the whole set.
+// Options.MaxMemory limits the estimated memory of the whole set.
+// The first error of any table stops the others and is returned, see ComputeCGContext for the possible errors.
+func ComputeAllContext(ctx context.Context, maxTwoj int, opts *Options) (*TableSet, error) {
+	if maxTwoj < 0 {
+		return nil, &InvalidJError{TwoJ1: maxTwoj, TwoJ2: maxTwoj}
+	}
+	if opts == nil {
+		opts = &Options{}
+	}
+	if opts.MaxMemory > 0 {
+		estimate := int64(0)
+		for twoj1 := 0; twoj1 <= maxTwoj; twoj1++ {
+			for twoj2 := 0; twoj2 <= twoj1; twoj2++ {
+				estimate += EstimateMemory(twoj1, twoj2)
+			}
+		}
+		if estimate > opts.MaxMemory {
+			return nil, &MemoryLimitError{Estimate: estimate, Limit: opts.MaxMemory}
+		}
+	}
+	ts := &TableSet{maxTwoj: maxTwoj, tables: make([]*Table, (maxTwoj+1)*(maxTwoj+2)/2)}
+	prog := newProgress(opts.Progress)
+	for twoj1 := 0; twoj1 <= maxTwoj; twoj1++ {
+		for twoj2 := 0; twoj2 <= twoj1; twoj2++ {
+			t := newTable(twoj1, twoj2)
+			prog.add(t.cellCount())
+			ts.tables[ts.index(twoj1, twoj2)] = t
+		}
+	}
+
+	pool := opts.Pool
+	if pool == nil {
+		pool = defaultPool()
+	}
+	// A failing table cancels the others, so the error recorded first is the one that caused it.
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		firstErr error
+	)
+	wg.Add(len(ts.tables))
+	for _, t := range ts.tables {
+		go func(c *computation) {
+			defer wg.Done()
+			if err := c.wait(); err != nil {
+				mu.Lock()
+				if firstErr == nil {
+					firstErr = err
+				}
+				mu.Unlock()
+				cancel()
+			}
+		}(startComputation(ctx, t, pool, prog))
+	}
+	wg.Wait()
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	return ts, nil
+}
+
+// Index of the table for j1 >= j2 in tables.
+func (ts *TableSet) index(twoj1, twoj2 int) int {
+	return twoj1*(twoj1+1)/2 + twoj2
+}
+
+// MaxJ returns the largest j1 and j2 of the tables in the set.
+func (ts *TableSet) MaxJ() HalfInteger {
+	return NewHalfInteger(ts.maxTwoj)
+}
+
+// Table returns the CG table for the given j1 and j2 in this order, or nil if either is out of the range of the set.
+// Arguments are twice the value of actual j1 and j2 so they are integers.
+func (ts *TableSet) Table(twoj1, twoj2 int) *Table {
+	if twoj1 < 0 || twoj2 < 0 || twoj1 > ts.maxTwoj || twoj2 > ts.maxTwoj {
+		return nil
+	}
+	if twoj1 < twoj2 {
+		return ts.tables[ts.index(twoj2, twoj1)].view(true)
+	}
+	return ts.tables[ts.index(twoj1, twoj2)]
+}
+
+// Each calls fn for the tables of every pair j1 >= j2 in the set, in order of increasing j1, then increasing j2.
+// The tables for j1 < j2 are available through Table. Iteration stops when fn returns false.
+func (ts *TableSet) Each(fn func(t *Table) bool) {
+	for _, t := range ts.tables {
+		if !fn(t) {
+			return
+		}
+	}
+}
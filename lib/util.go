@@ -1,38 +1,22 @@
 package cg
 
 import (
-	"fmt"
 	"math/big"
-	"strconv"
-	"strings"
 )
 
 // ParseHalfInteger parses half integer from string representation of half integer and returns its twice value integer.
+// See ParseHalfIntegerValue for the accepted forms.
 func ParseHalfInteger(str string) (int, error) {
-	parts := strings.Split(str, "/")
-	if len(parts) == 1 {
-		v, err := strconv.Atoi(parts[0])
-		if err != nil {
-			return 0, fmt.Errorf("invalid value for integer: '%v'", str)
-		}
-		return 2 * v, nil
-	}
-	if len(parts) != 2 || parts[1] != "2" {
-		return 0, fmt.Errorf("invalid format for half integer: '%v'", str)
-	}
-	v, err := strconv.Atoi(parts[0])
+	h, err := ParseHalfIntegerValue(str)
 	if err != nil {
-		return 0, fmt.Errorf("invalid value for half integer: '%v'", str)
+		return 0, err
 	}
-	return v, nil
+	return h.Twice(), nil
 }
 
 // FormatHalfInteger prints the half integer's value.
 func FormatHalfInteger(twiceValue int) string {
-	if twiceValue%2 == 0 {
-		return strconv.Itoa(twiceValue / 2)
-	}
-	return fmt.Sprintf("%v/2", twiceValue)
+	return NewHalfInteger(twiceValue).String()
 }
 
 // FormatRat formats the big.Rat.
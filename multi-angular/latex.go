@@ -2,7 +2,6 @@ package main
 
 import (
 	"fmt"
-	"strconv"
 	"strings"
 
 	cg "github.com/euphoricrhino/cg/lib"
@@ -17,17 +16,7 @@ func pathLatex(path []int) string {
 }
 
 func halfIntegerLatex(twov int) string {
-	str := ""
-	if twov < 0 {
-		str += "-"
-		twov = -twov
-	}
-	if twov%2 == 0 {
-		str += strconv.Itoa(twov / 2)
-	} else {
-		str += fmt.Sprintf("\\frac{%v}{2}", twov)
-	}
-	return str
+	return cg.NewHalfInteger(twov).LaTeX()
 }
 
 func jmLatex(twoj, twom int) string {
@@ -35,12 +24,14 @@ func jmLatex(twoj, twom int) string {
 }
 
 func stateLatex(st *state) string {
-	str := ""
-	num := cg.BlankInt().Abs(st.c.Num())
-	if st.c.Sign() < 0 {
-		str += "-"
+	// Omit unit coefficients, keeping the sign.
+	str := st.c.LaTeX()
+	switch str {
+	case "1":
+		str = ""
+	case "-1":
+		str = "-"
 	}
-	str += fmt.Sprintf("\\sqrt{\\frac{%v}{%v}}", num, st.c.Denom())
 	str += jmLatex(st.twoj, st.twom)
 	return str
 }
@@ -2,19 +2,45 @@ package main
 
 import (
 	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	cg "github.com/euphoricrhino/cg/lib"
 )
 
 var (
-	states = flag.String("states", "", "j1,m1;j2,m2[;...;jk,mk]")
+	states   = flag.String("states", "", "j1,m1;j2,m2[;...;jk,mk]")
+	cacheDir = flag.String("cache-dir", "", "directory to keep computed C-G tables in across runs, empty means no persistence")
+	format   = flag.String("format", "html", "output format, one of "+strings.Join(rendererNames(), ", "))
+	out      = flag.String("out", "", "file to write the result to, - for stdout; defaults to a new multi-angular-*.<extension of format> file in the temp directory")
 )
 
 func main() {
 	flag.Parse()
 
-	ma, err := computeMultiAngular(*states)
-	if err != nil {
-		panic(err)
+	if err := run(); err != nil {
+		fmt.Fprintf(os.Stderr, "multi-angular: %v\n", err)
+		os.Exit(1)
 	}
+}
 
-	ma.RenderHTML()
+func run() error {
+	r, ext, ok := lookupRenderer(*format)
+	if !ok {
+		return fmt.Errorf("unknown format %q, want one of %v", *format, strings.Join(rendererNames(), ", "))
+	}
+	ma, err := computeMultiAngular(*states, cg.NewCache(0, *cacheDir))
+	if err != nil {
+		return err
+	}
+	name, err := cg.WriteOutput(*out, "multi-angular-*."+ext, func(w io.Writer) error { return r.render(ma, w) })
+	if err != nil {
+		return err
+	}
+	if name != "-" {
+		fmt.Println(name)
+	}
+	return nil
 }
@@ -1,13 +1,16 @@
 package main
 
 import (
+	"bufio"
+	"context"
 	"fmt"
+	"io"
 	"math/big"
 	"os"
-	"path/filepath"
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
 
 	cg "github.com/euphoricrhino/cg/lib"
 )
@@ -18,7 +21,7 @@ var (
 
 // Represents an angular momentum eigenstate |j,m⟩ times a complex coefficient.
 type state struct {
-	c    *big.Rat
+	c    cg.SignedSqrt
 	twoj int
 	twom int
 	// Unique path identifying the subspace of dimension 2j+1.
@@ -43,30 +46,31 @@ func newState(jmStr string) (*state, error) {
 	if len(jmParts) != 2 {
 		return nil, errFormat
 	}
-	twoj, err := cg.ParseHalfInteger(jmParts[0])
+	j, err := cg.ParseHalfIntegerValue(jmParts[0])
 	if err != nil {
 		return nil, err
 	}
-	if twoj < 0 {
+	if j.Twice() < 0 {
 		return nil, fmt.Errorf("invalid j value: %v", jmParts[0])
 	}
-	twom, err := cg.ParseHalfInteger(jmParts[1])
+	m, err := cg.ParseHalfIntegerValue(jmParts[1])
 	if err != nil {
 		return nil, err
 	}
-	if twom > twoj || twom < -twoj {
-		return nil, fmt.Errorf("invalid m value for j=%v: %v", jmParts[1], jmParts[1])
+	if !cg.IsProjection(j, m) {
+		return nil, fmt.Errorf("invalid m value for j=%v: %v", jmParts[0], jmParts[1])
 	}
 	return &state{
-		c:            big.NewRat(1, 1),
-		twoj:         twoj,
-		twom:         twom,
-		subspacePath: []int{twoj},
+		c:            cg.NewSignedSqrt(big.NewRat(1, 1)),
+		twoj:         j.Twice(),
+		twom:         m.Twice(),
+		subspacePath: []int{j.Twice()},
 	}, nil
 }
 
 // Computes the multi angular decomposition given the input states.
-func computeMultiAngular(statesStr string) (*multiAngular, error) {
+// CG tables are taken from the given cache.
+func computeMultiAngular(statesStr string, cache *cg.Cache) (*multiAngular, error) {
 	parts := strings.Split(statesStr, ";")
 	if len(parts) <= 1 {
 		return nil, errFormat
@@ -125,49 +129,42 @@ func computeMultiAngular(statesStr string) (*multiAngular, error) {
 		subspaceIndex[pathToSubspaceKey(path)] = i
 	}
 
-	tables := make(map[string]*cg.Table)
+	// Construct the CG tables for all couplings along the subspace paths up front, so they are built in parallel.
+	var keys []tableKey
+	seen := make(map[tableKey]bool)
+	for _, path := range queue {
+		for i, st := range tail {
+			key := newTableKey(path[i], st.twoj)
+			if !seen[key] {
+				seen[key] = true
+				keys = append(keys, key)
+			}
+		}
+	}
+	pool := cg.NewPool(0)
+	defer pool.Close()
+	opts := &cg.Options{Pool: pool}
+	if err := prefetchTables(cache, keys, opts); err != nil {
+		return nil, err
+	}
 	// Now expand the tensor products into total angular momentum |j,m⟩ basis, consuming tail states one by one.
 	for len(tail) > 0 {
 		var st1, st2 *state
 		st2, tail = tail[0], tail[1:]
 		var hd []*state
 		for _, st1 = range head {
-			exchanged := false
-			jmax, jmin := st1.twoj, st2.twoj
-			if jmax < jmin {
-				jmax, jmin = jmin, jmax
-				exchanged = true
+			// The cache hands out the table in the order of j1 and j2 asked for.
+			t, err := cache.Get(context.Background(), st1.twoj, st2.twoj, opts)
+			if err != nil {
+				return nil, err
 			}
 			twom := st1.twom + st2.twom
-			// Trivial case, one of the j's is zero.
-			if jmin == 0 {
-				st := &state{
-					c:            st1.c,
-					twoj:         jmax,
-					twom:         twom,
-					subspacePath: appendCopy(st1.subspacePath, jmax),
-				}
-				hd = append(hd, st)
-				continue
-			}
-			tableKey := fmt.Sprintf("%v,%v", jmax, jmin)
-			t, found := tables[tableKey]
-			if !found {
-				// Construct the CG table for j1,j2.
-				fmt.Printf("constructing C-G table for j1=%v, j2=%v ...\n", cg.FormatHalfInteger(jmax), cg.FormatHalfInteger(jmin))
-				t = cg.ComputeCG(jmax, jmin)
-				tables[tableKey] = t
-			}
-			for twoj := jmax - jmin; twoj <= jmax+jmin; twoj += 2 {
-				var c *big.Rat
-				if !exchanged {
-					c = t.Query(twoj, twom, st1.twom, st2.twom)
-				} else {
-					c = t.ExchangedQuery(twoj, twom, st2.twom, st1.twom)
-				}
-				if c.Sign() != 0 {
+			key := newTableKey(st1.twoj, st2.twoj)
+			for twoj := key.jmax - key.jmin; twoj <= key.jmax+key.jmin; twoj += 2 {
+				c := t.QuerySqrt(twoj, twom, st1.twom, st2.twom)
+				if !c.IsZero() {
 					st := &state{
-						c:            cg.BlankRat().Mul(st1.c, c),
+						c:            st1.c.Mul(c),
 						twoj:         twoj,
 						twom:         twom,
 						subspacePath: appendCopy(st1.subspacePath, twoj),
@@ -186,6 +183,41 @@ func computeMultiAngular(statesStr string) (*multiAngular, error) {
 	}, nil
 }
 
+// Identifies the CG table for j1,j2 by their doubled values, with jmax >= jmin.
+type tableKey struct {
+	jmax int
+	jmin int
+}
+
+func newTableKey(twoj1, twoj2 int) tableKey {
+	if twoj1 < twoj2 {
+		twoj1, twoj2 = twoj2, twoj1
+	}
+	return tableKey{jmax: twoj1, jmin: twoj2}
+}
+
+// Brings the CG tables into the cache in parallel, sharing one worker pool so they do not oversubscribe the CPU.
+func prefetchTables(cache *cg.Cache, keys []tableKey, opts *cg.Options) error {
+	errs := make([]error, len(keys))
+	var wg sync.WaitGroup
+	wg.Add(len(keys))
+	for i, key := range keys {
+		fmt.Fprintf(os.Stderr, "constructing C-G table for j1=%v, j2=%v ...\n", cg.FormatHalfInteger(key.jmax), cg.FormatHalfInteger(key.jmin))
+		go func(i int, key tableKey) {
+			defer wg.Done()
+			_, errs[i] = cache.Get(context.Background(), key.jmax, key.jmin, opts)
+		}(i, key)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (ma *multiAngular) lookupSubspaceIndex(path []int) int {
 	idx, found := ma.subspaceIndex[pathToSubspaceKey(path)]
 	if !found {
@@ -194,44 +226,51 @@ func (ma *multiAngular) lookupSubspaceIndex(path []int) int {
 	return idx
 }
 
-// RenderHTML renders the multi angular decomposition.
-func (ma *multiAngular) RenderHTML() {
+// One line of the rendered decomposition: a description, then a relation between two LaTeX expressions.
+type decompositionLine struct {
+	label string
+	lhs   string
+	rel   string
+	rhs   string
+}
+
+// Returns the lines of the multi angular decomposition.
+func (ma *multiAngular) lines() []decompositionLine {
 	// Subspace compositions.
-	latexStr := "\\mbox{irreducible subspace compositions} & &"
-	for i, path := range ma.subspacePaths {
-		if i == 0 {
-			latexStr += fmt.Sprintf("%v&:%v", i, pathLatex(path))
-		} else {
-			latexStr += fmt.Sprintf("\\qquad %v:%v", i, pathLatex(path))
-		}
+	var s []string
+	for i, path := range ma.subspacePaths[1:] {
+		s = append(s, fmt.Sprintf("%v:%v", i+1, pathLatex(path)))
+	}
+	compositions := decompositionLine{
+		label: "irreducible subspace compositions",
+		lhs:   "0",
+		rel:   ":",
+		rhs:   pathLatex(ma.subspacePaths[0]),
+	}
+	if len(s) > 0 {
+		compositions.rhs += "\\qquad " + strings.Join(s, "\\qquad ")
 	}
-	latexStr += "\\\\\n"
 
-	latexStr += "\\mbox{irreducible subspace dimensions} & &"
 	// Tensor products of input dimensions.
-	var s []string
+	s = nil
 	for _, st := range ma.inputStates {
 		s = append(s, strconv.Itoa(st.twoj+1))
 	}
-	latexStr += strings.Join(s, "\\otimes ")
-	latexStr += " &= "
+	dimensions := decompositionLine{label: "irreducible subspace dimensions", lhs: strings.Join(s, "\\otimes "), rel: "="}
 	// Direct sums of irreducible subspace dimensions.
 	s = nil
 	for _, path := range ma.subspacePaths {
 		twoj := path[len(path)-1]
 		s = append(s, fmt.Sprintf("%v_{%v}", twoj+1, ma.lookupSubspaceIndex(path)))
 	}
-	latexStr += strings.Join(s, "\\oplus ")
-	latexStr += "\\\\\n"
+	dimensions.rhs = strings.Join(s, "\\oplus ")
 
 	// Tensor products of angular momenta.
 	s = nil
-	latexStr += "\\mbox{irreducible subspace total angular momenta} & &"
 	for _, st := range ma.inputStates {
 		s = append(s, halfIntegerLatex(st.twoj))
 	}
-	latexStr += strings.Join(s, "\\otimes ")
-	latexStr += " &= "
+	momenta := decompositionLine{label: "irreducible subspace total angular momenta", lhs: strings.Join(s, "\\otimes "), rel: "="}
 	s = nil
 	for _, path := range ma.subspacePaths {
 		twoj := path[len(path)-1]
@@ -241,42 +280,77 @@ func (ma *multiAngular) RenderHTML() {
 			s = append(s, fmt.Sprintf("%v_{%v}", twoj, ma.lookupSubspaceIndex(path)))
 		}
 	}
-	latexStr += strings.Join(s, "\\oplus ")
-	latexStr += "\\\\\n"
+	momenta.rhs = strings.Join(s, "\\oplus ")
 
 	// Tensor product states.
 	s = nil
 	for _, st := range ma.inputStates {
 		s = append(s, jmLatex(st.twoj, st.twom))
 	}
-	latexStr += "\\mbox{expansion in total angular momenta basis} & &"
-	latexStr += strings.Join(s, "\\otimes ")
-	latexStr += " &= "
+	expansion := decompositionLine{label: "expansion in total angular momenta basis", lhs: strings.Join(s, "\\otimes "), rel: "="}
 	// Expanded states.
 	if len(ma.expandedStates) == 0 {
-		latexStr += "0"
+		expansion.rhs = "0"
 	} else {
 		for i, st := range ma.expandedStates {
 			termStr := fmt.Sprintf("%v_{%v}", stateLatex(st), ma.lookupSubspaceIndex(st.subspacePath))
 			if i != 0 && termStr[0] != '-' {
-				latexStr += "+"
+				expansion.rhs += "+"
 			}
-			latexStr += termStr
+			expansion.rhs += termStr
 		}
 	}
-	latexStr += "\\\\\n"
+	return []decompositionLine{compositions, dimensions, momenta, expansion}
+}
 
-	filename := filepath.Join(os.TempDir(), "multi-angular.html")
-	f, err := os.Create(filename)
-	if err != nil {
-		panic(err)
+// Writes the multi angular decomposition as an HTML page, with the lines aligned in one display.
+func (ma *multiAngular) writeHTML(w io.Writer) error {
+	latexStr := ""
+	for _, l := range ma.lines() {
+		latexStr += fmt.Sprintf("\\mbox{%v} & &%v &%v %v\\\\\n", l.label, l.lhs, l.rel, l.rhs)
 	}
-	defer f.Close()
-	if err := tmpl.Execute(f, latexStr); err != nil {
-		panic(err)
+	if err := tmpl.Execute(w, latexStr); err != nil {
+		return &cg.WriteError{Err: err}
 	}
+	return nil
+}
+
+// Writes the multi angular decomposition as a Markdown document, with each line in display math.
+func (ma *multiAngular) writeMarkdown(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprint(bw, "# Multi angular decomposition\n")
+	for _, l := range ma.lines() {
+		fmt.Fprintf(bw, "\n%v%v:\n\n$$\n%v %v %v\n$$\n", strings.ToUpper(l.label[:1]), l.label[1:], l.lhs, l.rel, l.rhs)
+	}
+	if err := bw.Flush(); err != nil {
+		return &cg.WriteError{Err: err}
+	}
+	return nil
+}
 
-	fmt.Println(filename)
+// Writes the expansion as source code of a computer algebra system, assigning its coefficients to the variable expansion
+// keyed by the subspace path and m of each state |j,m⟩.
+func (ma *multiAngular) writeCAS(w io.Writer, lang cg.CASLanguage) error {
+	cw := cg.NewCASWriter(w, lang)
+	var s []string
+	for _, st := range ma.inputStates {
+		s = append(s, fmt.Sprintf("|%v,%v>", cg.FormatHalfInteger(st.twoj), cg.FormatHalfInteger(st.twom)))
+	}
+	cw.Comment(
+		fmt.Sprintf("Expansion of %v in total angular momentum states |j,m>.", strings.Join(s, "")),
+		"Keys are the path of j coupling the states from left to right, ending in the total j, and m.",
+		"Each coupling uses Clebsch-Gordan coefficients. "+lang.PhaseConvention(),
+	)
+	cw.Begin("expansion")
+	for _, st := range ma.expandedStates {
+		path := make([]string, len(st.subspacePath))
+		for i, twoj := range st.subspacePath {
+			path[i] = lang.HalfInteger(cg.NewHalfInteger(twoj))
+		}
+		cw.Entry(lang.Tuple(lang.Tuple(path...), lang.HalfInteger(cg.NewHalfInteger(st.twom))), st.c)
+	}
+	cw.End()
+	return cw.Flush()
 }
 
 func appendCopy(path []int, v int) []int {
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"io"
+	"sort"
+
+	cg "github.com/euphoricrhino/cg/lib"
+)
+
+// Renders the expansion to w in some output format, like cg.Renderer does for tables.
+type renderer interface {
+	render(ma *multiAngular, w io.Writer) error
+}
+
+type rendererFunc func(ma *multiAngular, w io.Writer) error
+
+func (f rendererFunc) render(ma *multiAngular, w io.Writer) error {
+	return f(ma, w)
+}
+
+// A renderer and the file extension of its output.
+type registeredRenderer struct {
+	r   renderer
+	ext string
+}
+
+// The renderers by format name, see lookupRenderer.
+var renderers = map[string]registeredRenderer{
+	"html":        {rendererFunc((*multiAngular).writeHTML), "html"},
+	"md":          {rendererFunc((*multiAngular).writeMarkdown), "md"},
+	"mathematica": {casRenderer(cg.Mathematica), "wl"},
+	"sympy":       {casRenderer(cg.SymPy), "py"},
+	"julia":       {casRenderer(cg.Julia), "jl"},
+}
+
+func casRenderer(lang cg.CASLanguage) renderer {
+	return rendererFunc(func(ma *multiAngular, w io.Writer) error { return ma.writeCAS(w, lang) })
+}
+
+// Returns the renderer for the format name and the file extension of its output, like cg.LookupRenderer.
+func lookupRenderer(name string) (renderer, string, bool) {
+	rr, ok := renderers[name]
+	return rr.r, rr.ext, ok
+}
+
+// Returns the names of all formats, sorted, like cg.RendererNames.
+func rendererNames() []string {
+	var names []string
+	for name := range renderers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}